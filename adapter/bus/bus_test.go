@@ -0,0 +1,80 @@
+package bus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageBus(t *testing.T) {
+	tests := []struct {
+		name   string
+		newBus func(t *testing.T) port.MessageBus
+	}{
+		{
+			name: "in-process",
+			newBus: func(t *testing.T) port.MessageBus {
+				return NewInProcessBus()
+			},
+		},
+		{
+			name: "redis",
+			newBus: func(t *testing.T) port.MessageBus {
+				s := miniredis.RunT(t)
+				client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+				t.Cleanup(func() { client.Close() })
+				return NewRedisBus(client)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			b := tt.newBus(t)
+			subscribe := func(topic entity.ID) (chan []byte, port.MessageBusSubscription) {
+				received := make(chan []byte, 8)
+				out, err := b.Subscribe(ctx, &port.SubscribeMessageBusInput{
+					Topic: topic,
+					Handler: func(ctx context.Context, payload []byte) {
+						received <- payload
+					},
+				})
+				require.NoError(t, err)
+				return received, out.Subscription
+			}
+			first, firstSub := subscribe("room")
+			second, _ := subscribe("room")
+
+			_, err := b.Publish(ctx, &port.PublishMessageBusInput{Topic: "other", Payload: []byte("other")})
+			require.NoError(t, err)
+			_, err = b.Publish(ctx, &port.PublishMessageBusInput{Topic: "room", Payload: []byte("hello")})
+			require.NoError(t, err)
+			assert.Equal(t, []byte("hello"), receive(t, first))
+			assert.Equal(t, []byte("hello"), receive(t, second))
+
+			require.NoError(t, firstSub.Unsubscribe(ctx))
+			_, err = b.Publish(ctx, &port.PublishMessageBusInput{Topic: "room", Payload: []byte("bye")})
+			require.NoError(t, err)
+			assert.Equal(t, []byte("bye"), receive(t, second))
+			assert.Empty(t, first)
+		})
+	}
+}
+
+func receive(t *testing.T, received chan []byte) []byte {
+	t.Helper()
+	select {
+	case payload := <-received:
+		return payload
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for payload")
+		return nil
+	}
+}
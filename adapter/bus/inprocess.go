@@ -0,0 +1,90 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ port.MessageBus = (*InProcessBus)(nil)
+
+// InProcessBus delivers payloads to the subscribers within the process.
+// Handlers run synchronously on the publisher's goroutine.
+type InProcessBus struct {
+	mux    sync.RWMutex
+	nextID uint64
+	topics map[entity.ID]map[uint64]port.MessageBusHandler
+}
+
+func NewInProcessBus() *InProcessBus {
+	return &InProcessBus{
+		topics: make(map[entity.ID]map[uint64]port.MessageBusHandler),
+	}
+}
+
+func (b *InProcessBus) Publish(ctx context.Context, input *port.PublishMessageBusInput) (*port.PublishMessageBusOutput, error) {
+	ctx, span := util.StartSpan(ctx, "bus.InProcessBus.Publish")
+	defer span.End()
+	// handlers are called outside the lock so that they may subscribe or unsubscribe
+	b.mux.RLock()
+	handlers := make([]port.MessageBusHandler, 0, len(b.topics[input.Topic]))
+	for _, handler := range b.topics[input.Topic] {
+		handlers = append(handlers, handler)
+	}
+	b.mux.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, input.Payload)
+	}
+	return &port.PublishMessageBusOutput{}, nil
+}
+
+func (b *InProcessBus) Subscribe(ctx context.Context, input *port.SubscribeMessageBusInput) (*port.SubscribeMessageBusOutput, error) {
+	_, span := util.StartSpan(ctx, "bus.InProcessBus.Subscribe")
+	defer span.End()
+	if input.Handler == nil {
+		return nil, errors.New("handler is required")
+	}
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.nextID++
+	id := b.nextID
+	if _, ok := b.topics[input.Topic]; !ok {
+		b.topics[input.Topic] = make(map[uint64]port.MessageBusHandler)
+	}
+	b.topics[input.Topic][id] = input.Handler
+
+	return &port.SubscribeMessageBusOutput{
+		Subscription: &inProcessSubscription{
+			bus:   b,
+			topic: input.Topic,
+			id:    id,
+		},
+	}, nil
+}
+
+func (b *InProcessBus) unsubscribe(topic entity.ID, id uint64) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	delete(b.topics[topic], id)
+	if len(b.topics[topic]) == 0 {
+		delete(b.topics, topic)
+	}
+}
+
+type inProcessSubscription struct {
+	bus   *InProcessBus
+	topic entity.ID
+	id    uint64
+}
+
+func (s *inProcessSubscription) Unsubscribe(ctx context.Context) error {
+	_, span := util.StartSpan(ctx, "bus.inProcessSubscription.Unsubscribe")
+	defer span.End()
+	s.bus.unsubscribe(s.topic, s.id)
+	return nil
+}
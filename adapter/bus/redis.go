@@ -0,0 +1,74 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+	"github.com/redis/go-redis/v9"
+)
+
+var _ port.MessageBus = (*RedisBus)(nil)
+
+const redisChannelPrefix = "go-ws-sample:"
+
+// RedisBus delivers payloads through Redis pub/sub, so every server instance
+// connected to the same Redis receives them.
+// Each subscription holds its own pub/sub connection.
+type RedisBus struct {
+	client redis.UniversalClient
+}
+
+func NewRedisBus(client redis.UniversalClient) *RedisBus {
+	return &RedisBus{
+		client: client,
+	}
+}
+
+func (b *RedisBus) Publish(ctx context.Context, input *port.PublishMessageBusInput) (*port.PublishMessageBusOutput, error) {
+	ctx, span := util.StartSpan(ctx, "bus.RedisBus.Publish")
+	defer span.End()
+	if err := b.client.Publish(ctx, redisChannelPrefix+input.Topic.String(), input.Payload).Err(); err != nil {
+		return nil, fmt.Errorf("failed to publish: %w", err)
+	}
+	return &port.PublishMessageBusOutput{}, nil
+}
+
+func (b *RedisBus) Subscribe(ctx context.Context, input *port.SubscribeMessageBusInput) (*port.SubscribeMessageBusOutput, error) {
+	ctx, span := util.StartSpan(ctx, "bus.RedisBus.Subscribe")
+	defer span.End()
+	if input.Handler == nil {
+		return nil, errors.New("handler is required")
+	}
+	pubsub := b.client.Subscribe(ctx, redisChannelPrefix+input.Topic.String())
+	// wait for the confirmation, messages published before it are not delivered
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	handlerCtx := context.WithoutCancel(ctx)
+	go func() {
+		for msg := range pubsub.Channel() {
+			input.Handler(handlerCtx, []byte(msg.Payload))
+		}
+	}()
+
+	return &port.SubscribeMessageBusOutput{
+		Subscription: &redisSubscription{
+			pubsub: pubsub,
+		},
+	}, nil
+}
+
+type redisSubscription struct {
+	pubsub *redis.PubSub
+}
+
+func (s *redisSubscription) Unsubscribe(ctx context.Context) error {
+	_, span := util.StartSpan(ctx, "bus.redisSubscription.Unsubscribe")
+	defer span.End()
+	return s.pubsub.Close()
+}
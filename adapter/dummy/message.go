@@ -0,0 +1,170 @@
+package dummy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var (
+	_ port.MessagesReader = (*MessagesAccess)(nil)
+	_ port.MessagesWriter = (*MessagesAccess)(nil)
+)
+
+type idempotencyKey struct {
+	roomID entity.ID
+	userID entity.ID
+	key    string
+}
+
+type MessagesAccess struct {
+	mux         sync.RWMutex
+	idGenerator port.IDGenerator
+	// messages holds the messages of each room in posted order.
+	messages map[entity.ID]entity.PostMessages
+	keys     map[idempotencyKey]*entity.PostMessage
+}
+
+// NewMessagesAccess returns the messages of the rooms in rooms, which are deleted along with their room.
+func NewMessagesAccess(idGenerator port.IDGenerator, rooms *RoomsAccess) *MessagesAccess {
+	a := &MessagesAccess{
+		idGenerator: idGenerator,
+		messages:    make(map[entity.ID]entity.PostMessages),
+		keys:        make(map[idempotencyKey]*entity.PostMessage),
+	}
+	rooms.mux.Lock()
+	defer rooms.mux.Unlock()
+	rooms.onDelete = append(rooms.onDelete, a.deleteRoom)
+	return a
+}
+
+func (a *MessagesAccess) Find(ctx context.Context, input *port.FindMessagesInput) (*port.FindMessagesOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.MessagesAccess.Find")
+	defer span.End()
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+
+	messages := a.messages[input.RoomID]
+	found := entity.PostMessages{}
+	if input.After != nil {
+		for _, message := range messages {
+			if input.Limit > 0 && len(found) >= input.Limit {
+				break
+			}
+			if message.ID <= *input.After {
+				continue
+			}
+			if input.Before != nil && message.ID >= *input.Before {
+				break
+			}
+			found = append(found, message)
+		}
+		return &port.FindMessagesOutput{
+			Messages: found,
+		}, nil
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if input.Limit > 0 && len(found) >= input.Limit {
+			break
+		}
+		if input.Before != nil && messages[i].ID >= *input.Before {
+			continue
+		}
+		found = append(found, messages[i])
+	}
+
+	return &port.FindMessagesOutput{
+		Messages: found,
+	}, nil
+}
+
+func (a *MessagesAccess) Create(ctx context.Context, input *port.CreateMessageInput) (*port.CreateMessageOutput, error) {
+	ctx, span := util.StartSpan(ctx, "dummy.MessagesAccess.Create")
+	defer span.End()
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	var key *idempotencyKey
+	if len(input.IdempotencyKey) > 0 && input.PostedBy != nil {
+		key = &idempotencyKey{
+			roomID: input.RoomID,
+			userID: input.PostedBy.ID,
+			key:    input.IdempotencyKey,
+		}
+		if message, ok := a.keys[*key]; ok {
+			return &port.CreateMessageOutput{
+				Message: message,
+				Created: false,
+			}, nil
+		}
+	}
+
+	id, err := a.idGenerator.Generate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate id: %w", err)
+	}
+	now := time.Now()
+	message := entity.PostMessage{
+		ID:             id,
+		RoomID:         input.RoomID,
+		Body:           input.Body,
+		PostedDatetime: &now,
+		PostedBy:       input.PostedBy,
+	}
+	a.messages[input.RoomID] = append(a.messages[input.RoomID], &message)
+	if key != nil {
+		a.keys[*key] = &message
+	}
+
+	return &port.CreateMessageOutput{
+		Message: &message,
+		Created: true,
+	}, nil
+}
+
+func (a *MessagesAccess) Delete(ctx context.Context, input *port.DeleteMessagesInput) (*port.DeleteMessagesOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.MessagesAccess.Delete")
+	defer span.End()
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	if input.MessageID != nil {
+		messages := a.messages[input.RoomID]
+		idx := slices.IndexFunc(messages, func(m *entity.PostMessage) bool {
+			return m.ID == *input.MessageID
+		})
+		if idx < 0 {
+			return nil, usecase.ErrNotFoundEntity
+		}
+		a.messages[input.RoomID] = slices.Delete(slices.Clone(messages), idx, idx+1)
+		maps.DeleteFunc(a.keys, func(_ idempotencyKey, m *entity.PostMessage) bool {
+			return m.ID == *input.MessageID
+		})
+		return &port.DeleteMessagesOutput{}, nil
+	}
+
+	a.deleteRoomLocked(input.RoomID)
+
+	return &port.DeleteMessagesOutput{}, nil
+}
+
+func (a *MessagesAccess) deleteRoom(roomID entity.ID) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.deleteRoomLocked(roomID)
+}
+
+func (a *MessagesAccess) deleteRoomLocked(roomID entity.ID) {
+	delete(a.messages, roomID)
+	maps.DeleteFunc(a.keys, func(key idempotencyKey, _ *entity.PostMessage) bool {
+		return key.roomID == roomID
+	})
+}
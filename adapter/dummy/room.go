@@ -1,14 +1,17 @@
 package dummy
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 	"sync"
 
 	"github.com/mkaiho/go-ws-sample/entity"
 	"github.com/mkaiho/go-ws-sample/usecase"
 	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
 )
 
 var (
@@ -20,6 +23,8 @@ type RoomsAccess struct {
 	mux         sync.RWMutex
 	idGenerator port.IDGenerator
 	rooms       entity.Rooms
+	// onDelete cascades the deletion of a room to the stores referencing it, with the lock held.
+	onDelete []func(roomID entity.ID)
 }
 
 func NewRoomsAccess(idGenerator port.IDGenerator) *RoomsAccess {
@@ -29,14 +34,43 @@ func NewRoomsAccess(idGenerator port.IDGenerator) *RoomsAccess {
 }
 
 func (a *RoomsAccess) Find(ctx context.Context, input *port.FindRoomsInput) (*port.FindRoomsOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.RoomsAccess.Find")
+	defer span.End()
 	a.mux.RLock()
 	defer a.mux.RUnlock()
+
+	query := strings.ToLower(input.Query)
+	rooms := slices.DeleteFunc(slices.Clone(a.rooms), func(r *entity.Room) bool {
+		return !strings.Contains(strings.ToLower(r.Name), query)
+	})
+	compare := func(r *entity.Room, p *port.RoomsPosition) int {
+		if input.Sort == port.RoomsSortKeyName {
+			if c := cmp.Compare(r.Name, p.Name); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(r.ID, p.ID)
+	}
+	slices.SortFunc(rooms, func(x, y *entity.Room) int {
+		return compare(x, &port.RoomsPosition{ID: y.ID, Name: y.Name})
+	})
+	if input.After != nil {
+		rooms = slices.DeleteFunc(rooms, func(r *entity.Room) bool {
+			return compare(r, input.After) <= 0
+		})
+	}
+	if input.Limit > 0 && len(rooms) > input.Limit {
+		rooms = rooms[:input.Limit]
+	}
+
 	return &port.FindRoomsOutput{
-		Rooms: append(entity.Rooms{}, a.rooms...),
+		Rooms: rooms,
 	}, nil
 }
 
 func (a *RoomsAccess) Get(ctx context.Context, input *port.GetRoomInput) (*port.GetRoomOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.RoomsAccess.Get")
+	defer span.End()
 	a.mux.RLock()
 	defer a.mux.RUnlock()
 	for _, room := range a.rooms {
@@ -50,6 +84,8 @@ func (a *RoomsAccess) Get(ctx context.Context, input *port.GetRoomInput) (*port.
 }
 
 func (a *RoomsAccess) Create(ctx context.Context, input *port.CreateRoomInput) (*port.CreateRoomOutput, error) {
+	ctx, span := util.StartSpan(ctx, "dummy.RoomsAccess.Create")
+	defer span.End()
 	a.mux.Lock()
 	defer a.mux.Unlock()
 
@@ -61,6 +97,15 @@ func (a *RoomsAccess) Create(ctx context.Context, input *port.CreateRoomInput) (
 		ID:          id,
 		Name:        input.Name,
 		Description: input.Description,
+		Version:     1,
+		Members:     entity.Members{},
+	}
+	if input.Owner != nil {
+		owner := *input.Owner
+		room.Members = append(room.Members, &entity.Member{
+			User: &owner,
+			Role: entity.RoleOwner,
+		})
 	}
 	a.rooms = append(a.rooms, &room)
 
@@ -69,13 +114,144 @@ func (a *RoomsAccess) Create(ctx context.Context, input *port.CreateRoomInput) (
 	}, nil
 }
 
+func (a *RoomsAccess) Update(ctx context.Context, input *port.UpdateRoomInput) (*port.UpdateRoomOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.RoomsAccess.Update")
+	defer span.End()
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	idx := a.index(input.ID)
+	if idx < 0 {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	if input.Version != nil && *input.Version != a.rooms[idx].Version {
+		return nil, usecase.ErrVersionConflict
+	}
+	// rooms are replaced rather than modified because they are handed out by pointer
+	room := *a.rooms[idx]
+	room.Version++
+	if input.Name != nil {
+		room.Name = *input.Name
+	}
+	if input.Description != nil {
+		room.Description = *input.Description
+	}
+	a.rooms[idx] = &room
+
+	return &port.UpdateRoomOutput{
+		Room: &room,
+	}, nil
+}
+
 func (a *RoomsAccess) Delete(ctx context.Context, input *port.DeleteRoomInput) (*port.DeleteRoomOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.RoomsAccess.Delete")
+	defer span.End()
 	a.mux.Lock()
 	defer a.mux.Unlock()
 
-	a.rooms = slices.DeleteFunc(a.rooms, func(r *entity.Room) bool {
-		return r.ID == input.ID
-	})
+	idx := a.index(input.ID)
+	if idx < 0 {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	if input.Version != nil && *input.Version != a.rooms[idx].Version {
+		return nil, usecase.ErrVersionConflict
+	}
+	a.rooms = slices.Delete(a.rooms, idx, idx+1)
+	for _, cascade := range a.onDelete {
+		cascade(input.ID)
+	}
 
 	return &port.DeleteRoomOutput{}, nil
 }
+
+func (a *RoomsAccess) FindMembers(ctx context.Context, input *port.FindRoomMembersInput) (*port.FindRoomMembersOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.RoomsAccess.FindMembers")
+	defer span.End()
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+	room := a.find(input.RoomID)
+	if room == nil {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	return &port.FindRoomMembersOutput{
+		Members: append(entity.Members{}, room.Members...),
+	}, nil
+}
+
+func (a *RoomsAccess) GetMember(ctx context.Context, input *port.GetRoomMemberInput) (*port.GetRoomMemberOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.RoomsAccess.GetMember")
+	defer span.End()
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+	room := a.find(input.RoomID)
+	if room == nil {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	member := room.Members.Find(input.UserID)
+	if member == nil {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	return &port.GetRoomMemberOutput{
+		Member: member,
+	}, nil
+}
+
+func (a *RoomsAccess) AddMember(ctx context.Context, input *port.AddRoomMemberInput) (*port.AddRoomMemberOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.RoomsAccess.AddMember")
+	defer span.End()
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	idx := a.index(input.RoomID)
+	if idx < 0 {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	if a.rooms[idx].Members.Find(input.User.ID) != nil {
+		return nil, usecase.ErrAlreadyExistsEntity
+	}
+	user := *input.User
+	member := entity.Member{
+		User: &user,
+		Role: input.Role,
+	}
+	// rooms are replaced rather than modified because they are handed out by pointer
+	room := *a.rooms[idx]
+	room.Members = append(slices.Clone(room.Members), &member)
+	a.rooms[idx] = &room
+
+	return &port.AddRoomMemberOutput{
+		Member: &member,
+	}, nil
+}
+
+func (a *RoomsAccess) RemoveMember(ctx context.Context, input *port.RemoveRoomMemberInput) (*port.RemoveRoomMemberOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.RoomsAccess.RemoveMember")
+	defer span.End()
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	idx := a.index(input.RoomID)
+	if idx < 0 {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	room := *a.rooms[idx]
+	room.Members = slices.DeleteFunc(slices.Clone(room.Members), func(m *entity.Member) bool {
+		return m.User.ID == input.UserID
+	})
+	if len(room.Members) == len(a.rooms[idx].Members) {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	a.rooms[idx] = &room
+
+	return &port.RemoveRoomMemberOutput{}, nil
+}
+
+func (a *RoomsAccess) find(id entity.ID) *entity.Room {
+	if idx := a.index(id); idx >= 0 {
+		return a.rooms[idx]
+	}
+	return nil
+}
+
+func (a *RoomsAccess) index(id entity.ID) int {
+	return slices.IndexFunc(a.rooms, func(r *entity.Room) bool {
+		return r.ID == id
+	})
+}
@@ -0,0 +1,88 @@
+package dummy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var (
+	_ port.SanctionsReader = (*SanctionsAccess)(nil)
+	_ port.SanctionsWriter = (*SanctionsAccess)(nil)
+)
+
+type sanctionKey struct {
+	roomID entity.ID
+	userID entity.ID
+	typ    entity.SanctionType
+}
+
+type SanctionsAccess struct {
+	mux       sync.RWMutex
+	sanctions map[sanctionKey]*entity.Sanction
+}
+
+// NewSanctionsAccess returns the sanctions of the rooms in rooms, which are deleted along with their room.
+func NewSanctionsAccess(rooms *RoomsAccess) *SanctionsAccess {
+	a := &SanctionsAccess{
+		sanctions: make(map[sanctionKey]*entity.Sanction),
+	}
+	rooms.mux.Lock()
+	defer rooms.mux.Unlock()
+	rooms.onDelete = append(rooms.onDelete, a.deleteRoom)
+	return a
+}
+
+func (a *SanctionsAccess) Get(ctx context.Context, input *port.GetSanctionInput) (*port.GetSanctionOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.SanctionsAccess.Get")
+	defer span.End()
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+	sanction, ok := a.sanctions[sanctionKey{roomID: input.RoomID, userID: input.UserID, typ: input.Type}]
+	if !ok || !sanction.Active(time.Now()) {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	return &port.GetSanctionOutput{
+		Sanction: sanction,
+	}, nil
+}
+
+func (a *SanctionsAccess) Put(ctx context.Context, input *port.PutSanctionInput) (*port.PutSanctionOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.SanctionsAccess.Put")
+	defer span.End()
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	sanction := *input.Sanction
+	a.sanctions[sanctionKey{roomID: sanction.RoomID, userID: sanction.UserID, typ: sanction.Type}] = &sanction
+	return &port.PutSanctionOutput{
+		Sanction: &sanction,
+	}, nil
+}
+
+func (a *SanctionsAccess) Delete(ctx context.Context, input *port.DeleteSanctionInput) (*port.DeleteSanctionOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.SanctionsAccess.Delete")
+	defer span.End()
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	key := sanctionKey{roomID: input.RoomID, userID: input.UserID, typ: input.Type}
+	if _, ok := a.sanctions[key]; !ok {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	delete(a.sanctions, key)
+	return &port.DeleteSanctionOutput{}, nil
+}
+
+func (a *SanctionsAccess) deleteRoom(roomID entity.ID) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	for key := range a.sanctions {
+		if key.roomID == roomID {
+			delete(a.sanctions, key)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package dummy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var (
+	_ port.UsersReader = (*UsersAccess)(nil)
+	_ port.UsersWriter = (*UsersAccess)(nil)
+)
+
+type userRecord struct {
+	user         *entity.User
+	passwordHash []byte
+}
+
+type UsersAccess struct {
+	mux         sync.RWMutex
+	idGenerator port.IDGenerator
+	users       []*userRecord
+}
+
+func NewUsersAccess(idGenerator port.IDGenerator) *UsersAccess {
+	return &UsersAccess{
+		idGenerator: idGenerator,
+	}
+}
+
+func (a *UsersAccess) Get(ctx context.Context, input *port.GetUserInput) (*port.GetUserOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.UsersAccess.Get")
+	defer span.End()
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+	for _, record := range a.users {
+		if record.user.ID == input.ID {
+			return &port.GetUserOutput{
+				User: record.user,
+			}, nil
+		}
+	}
+	return nil, usecase.ErrNotFoundEntity
+}
+
+func (a *UsersAccess) GetCredential(ctx context.Context, input *port.GetUserCredentialInput) (*port.GetUserCredentialOutput, error) {
+	_, span := util.StartSpan(ctx, "dummy.UsersAccess.GetCredential")
+	defer span.End()
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+	for _, record := range a.users {
+		if record.user.Name == input.Name {
+			return &port.GetUserCredentialOutput{
+				User:         record.user,
+				PasswordHash: record.passwordHash,
+			}, nil
+		}
+	}
+	return nil, usecase.ErrNotFoundEntity
+}
+
+func (a *UsersAccess) Create(ctx context.Context, input *port.CreateUserInput) (*port.CreateUserOutput, error) {
+	ctx, span := util.StartSpan(ctx, "dummy.UsersAccess.Create")
+	defer span.End()
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	for _, record := range a.users {
+		if record.user.Name == input.Name {
+			return nil, usecase.ErrAlreadyExistsEntity
+		}
+	}
+	id, err := a.idGenerator.Generate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate id: %w", err)
+	}
+	user := entity.User{
+		ID:   id,
+		Name: input.Name,
+	}
+	a.users = append(a.users, &userRecord{
+		user:         &user,
+		passwordHash: input.PasswordHash,
+	})
+
+	return &port.CreateUserOutput{
+		User: &user,
+	}, nil
+}
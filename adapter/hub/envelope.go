@@ -0,0 +1,109 @@
+package hub
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+)
+
+// envelope is the payload a RoomHub publishes to the message bus.
+type envelope struct {
+	Type          port.RoomEventType `json:"type"`
+	RoomID        entity.ID          `json:"room_id"`
+	ExcludeConnID entity.ID          `json:"exclude_conn_id,omitempty"`
+	Message       *envelopeMessage   `json:"message,omitempty"`
+	Room          *envelopeRoom      `json:"room,omitempty"`
+	UserID        entity.ID          `json:"user_id,omitempty"`
+}
+
+type envelopeMessage struct {
+	ID             entity.ID     `json:"id"`
+	RoomID         entity.ID     `json:"room_id"`
+	Body           string        `json:"body"`
+	PostedDatetime *time.Time    `json:"posted_datetime,omitempty"`
+	PostedBy       *envelopeUser `json:"posted_by,omitempty"`
+}
+
+type envelopeRoom struct {
+	ID          entity.ID `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	Version     int       `json:"version"`
+}
+
+type envelopeUser struct {
+	ID   entity.ID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func encodeEnvelope(input *port.BroadcastRoomHubInput) ([]byte, error) {
+	e := envelope{
+		Type:          input.Event.Type,
+		RoomID:        input.Event.RoomID,
+		ExcludeConnID: input.ExcludeConnID,
+		UserID:        input.Event.UserID,
+	}
+	if m := input.Event.Message; m != nil {
+		e.Message = &envelopeMessage{
+			ID:             m.ID,
+			RoomID:         m.RoomID,
+			Body:           m.Body,
+			PostedDatetime: m.PostedDatetime,
+		}
+		if m.PostedBy != nil {
+			e.Message.PostedBy = &envelopeUser{
+				ID:   m.PostedBy.ID,
+				Name: m.PostedBy.Name,
+			}
+		}
+	}
+	if r := input.Event.Room; r != nil {
+		e.Room = &envelopeRoom{
+			ID:          r.ID,
+			Name:        r.Name,
+			Description: r.Description,
+			Version:     r.Version,
+		}
+	}
+	return json.Marshal(&e)
+}
+
+func decodeEnvelope(payload []byte) (*port.BroadcastRoomHubInput, error) {
+	var e envelope
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, err
+	}
+	event := port.RoomEvent{
+		Type:   e.Type,
+		RoomID: e.RoomID,
+		UserID: e.UserID,
+	}
+	if m := e.Message; m != nil {
+		event.Message = &entity.PostMessage{
+			ID:             m.ID,
+			RoomID:         m.RoomID,
+			Body:           m.Body,
+			PostedDatetime: m.PostedDatetime,
+		}
+		if m.PostedBy != nil {
+			event.Message.PostedBy = &entity.User{
+				ID:   m.PostedBy.ID,
+				Name: m.PostedBy.Name,
+			}
+		}
+	}
+	if r := e.Room; r != nil {
+		event.Room = &entity.Room{
+			ID:          r.ID,
+			Name:        r.Name,
+			Description: r.Description,
+			Version:     r.Version,
+		}
+	}
+	return &port.BroadcastRoomHubInput{
+		Event:         &event,
+		ExcludeConnID: e.ExcludeConnID,
+	}, nil
+}
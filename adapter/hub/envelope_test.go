@@ -0,0 +1,67 @@
+package hub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	postedAt := time.Date(2024, 2, 8, 0, 0, 0, 0, time.UTC)
+	description := "description"
+	tests := []struct {
+		name  string
+		input *port.BroadcastRoomHubInput
+	}{
+		{
+			name: "message created",
+			input: &port.BroadcastRoomHubInput{
+				Event: &port.RoomEvent{
+					Type:   port.RoomEventTypeMessageCreated,
+					RoomID: "room",
+					Message: &entity.PostMessage{
+						ID:             "01",
+						RoomID:         "room",
+						Body:           "hello",
+						PostedDatetime: &postedAt,
+						PostedBy:       &entity.User{ID: "user", Name: "alice"},
+					},
+				},
+				ExcludeConnID: "conn",
+			},
+		},
+		{
+			name: "room updated",
+			input: &port.BroadcastRoomHubInput{
+				Event: &port.RoomEvent{
+					Type:   port.RoomEventTypeRoomUpdated,
+					RoomID: "room",
+					Room:   &entity.Room{ID: "room", Name: "renamed", Description: &description, Version: 2},
+				},
+			},
+		},
+		{
+			name: "user kicked",
+			input: &port.BroadcastRoomHubInput{
+				Event: &port.RoomEvent{
+					Type:   port.RoomEventTypeUserKicked,
+					RoomID: "room",
+					UserID: "user",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := encodeEnvelope(tt.input)
+			require.NoError(t, err)
+			got, err := decodeEnvelope(payload)
+			require.NoError(t, err)
+			assert.Equal(t, tt.input, got)
+		})
+	}
+}
@@ -0,0 +1,481 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mkaiho/go-ws-sample/adapter/metrics"
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ port.RoomHub = (*RoomHub)(nil)
+
+var ErrRoomHubClosed = errors.New("room hub is closed")
+
+const defaultQueueSize = 64
+
+type SlowConsumerPolicy int
+
+const (
+	// SlowConsumerPolicyDisconnect closes the connection whose queue is full.
+	SlowConsumerPolicyDisconnect SlowConsumerPolicy = iota
+	// SlowConsumerPolicyDrop discards the event for the connection whose queue is full.
+	SlowConsumerPolicyDrop
+)
+
+type roomHubOption interface {
+	apply(*roomHubConf)
+}
+
+type roomHubConf struct {
+	QueueSize          int
+	SlowConsumerPolicy SlowConsumerPolicy
+	Metrics            port.Metrics
+}
+
+type QueueSizeOption int
+
+func (o QueueSizeOption) apply(c *roomHubConf) {
+	if o > 0 {
+		c.QueueSize = int(o)
+	}
+}
+
+func OptionQueueSize(v int) QueueSizeOption {
+	return QueueSizeOption(v)
+}
+
+type SlowConsumerPolicyOption SlowConsumerPolicy
+
+func (o SlowConsumerPolicyOption) apply(c *roomHubConf) {
+	policy := SlowConsumerPolicy(o)
+	if policy >= SlowConsumerPolicyDisconnect && policy <= SlowConsumerPolicyDrop {
+		c.SlowConsumerPolicy = policy
+	}
+}
+
+func OptionSlowConsumerPolicy(v SlowConsumerPolicy) SlowConsumerPolicyOption {
+	return SlowConsumerPolicyOption(v)
+}
+
+type MetricsOption struct {
+	metrics port.Metrics
+}
+
+func (o MetricsOption) apply(c *roomHubConf) {
+	if o.metrics != nil {
+		c.Metrics = o.metrics
+	}
+}
+
+// OptionMetrics records the connections, delivered messages and slow consumers of the hub.
+func OptionMetrics(v port.Metrics) MetricsOption {
+	return MetricsOption{metrics: v}
+}
+
+type client struct {
+	id     entity.ID
+	roomID entity.ID
+	userID entity.ID
+	conn   port.RoomHubConn
+	queue  chan *port.RoomEvent
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (c *client) stop() {
+	c.once.Do(func() {
+		close(c.done)
+	})
+}
+
+type room struct {
+	clients      map[entity.ID]*client
+	subscription port.MessageBusSubscription
+	// subscribed is closed when the subscription is done, err is set when it failed
+	subscribed chan struct{}
+	err        error
+}
+
+// RoomHub fans out events to the connections of each room.
+// Events are published to the message bus and delivered to the local connections
+// by the bus subscription each room holds while it has connections on this instance.
+// Every connection has its own bounded queue drained by a dedicated writer goroutine,
+// so a stalled connection never blocks the delivery to the others.
+type RoomHub struct {
+	mux         sync.RWMutex
+	idGenerator port.IDGenerator
+	bus         port.MessageBus
+	conf        roomHubConf
+	rooms       map[entity.ID]*room
+	closed      bool
+	closing     chan struct{}
+	closeOnce   sync.Once
+	writers     sync.WaitGroup
+}
+
+func NewRoomHub(idGenerator port.IDGenerator, bus port.MessageBus, options ...roomHubOption) *RoomHub {
+	conf := roomHubConf{
+		QueueSize:          defaultQueueSize,
+		SlowConsumerPolicy: SlowConsumerPolicyDisconnect,
+		Metrics:            metrics.NopMetrics{},
+	}
+	for _, opt := range options {
+		opt.apply(&conf)
+	}
+	return &RoomHub{
+		idGenerator: idGenerator,
+		bus:         bus,
+		conf:        conf,
+		rooms:       make(map[entity.ID]*room),
+		closing:     make(chan struct{}),
+	}
+}
+
+func (h *RoomHub) Join(ctx context.Context, input *port.JoinRoomHubInput) (*port.JoinRoomHubOutput, error) {
+	ctx, span := util.StartSpan(ctx, "hub.RoomHub.Join")
+	defer span.End()
+	if input.Conn == nil {
+		return nil, errors.New("connection is required")
+	}
+	id, err := h.idGenerator.Generate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate id: %w", err)
+	}
+	c := &client{
+		id:     id,
+		roomID: input.RoomID,
+		userID: input.UserID,
+		conn:   input.Conn,
+		queue:  make(chan *port.RoomEvent, h.conf.QueueSize),
+		done:   make(chan struct{}),
+	}
+
+	bgCtx := util.NewContextWithLogger(context.Background(), util.FromContext(ctx))
+
+	for {
+		r, err := h.subscribe(ctx, bgCtx, c.roomID)
+		if err != nil {
+			return nil, err
+		}
+		h.mux.Lock()
+		if h.closed {
+			h.release(ctx, c.roomID, r)
+			return nil, ErrRoomHubClosed
+		}
+		// the room may have been emptied and unsubscribed while this Join was waiting for it
+		if h.rooms[c.roomID] == r {
+			r.clients[c.id] = c
+			h.writers.Add(1)
+			h.mux.Unlock()
+			break
+		}
+		h.mux.Unlock()
+	}
+	h.conf.Metrics.AddConnections(ctx, c.roomID, 1)
+
+	go h.write(bgCtx, c)
+
+	return &port.JoinRoomHubOutput{
+		ConnID: c.id,
+	}, nil
+}
+
+// subscribe returns the room subscribed to the message bus.
+// The first Join of a room subscribes it outside the lock, so a slow bus does not block
+// the other rooms, and the concurrent Joins of the same room wait for that subscription.
+func (h *RoomHub) subscribe(ctx context.Context, bgCtx context.Context, roomID entity.ID) (*room, error) {
+	h.mux.Lock()
+	if h.closed {
+		h.mux.Unlock()
+		return nil, ErrRoomHubClosed
+	}
+	r, ok := h.rooms[roomID]
+	if !ok {
+		r = &room{
+			clients:    make(map[entity.ID]*client),
+			subscribed: make(chan struct{}),
+		}
+		h.rooms[roomID] = r
+	}
+	h.mux.Unlock()
+
+	if ok {
+		select {
+		case <-r.subscribed:
+			return r, r.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	out, err := h.bus.Subscribe(bgCtx, &port.SubscribeMessageBusInput{
+		Topic:   roomID,
+		Handler: h.deliver,
+	})
+	h.mux.Lock()
+	if err != nil {
+		// the room has no connections until it is subscribed, so nothing else removed it
+		delete(h.rooms, roomID)
+		r.err = fmt.Errorf("failed to subscribe room: %w", err)
+	} else {
+		r.subscription = out.Subscription
+	}
+	close(r.subscribed)
+	h.mux.Unlock()
+	return r, r.err
+}
+
+// release removes the room subscribed for a Join which could not join it, unless other connections joined it.
+// It must be called with the lock held and unlocks it.
+func (h *RoomHub) release(ctx context.Context, roomID entity.ID, r *room) {
+	emptied := h.rooms[roomID] == r && len(r.clients) == 0
+	if emptied {
+		delete(h.rooms, roomID)
+	}
+	h.mux.Unlock()
+	if emptied {
+		if err := r.subscription.Unsubscribe(ctx); err != nil {
+			util.FromContext(ctx).Error(err, "failed to unsubscribe room", "roomID", roomID)
+		}
+	}
+}
+
+func (h *RoomHub) Leave(ctx context.Context, input *port.LeaveRoomHubInput) (*port.LeaveRoomHubOutput, error) {
+	ctx, span := util.StartSpan(ctx, "hub.RoomHub.Leave")
+	defer span.End()
+	if c := h.remove(ctx, input.RoomID, input.ConnID); c != nil {
+		c.stop()
+	}
+	return &port.LeaveRoomHubOutput{}, nil
+}
+
+// Broadcast publishes the event to the message bus, the connections of every instance receive it.
+func (h *RoomHub) Broadcast(ctx context.Context, input *port.BroadcastRoomHubInput) (*port.BroadcastRoomHubOutput, error) {
+	ctx, span := util.StartSpan(ctx, "hub.RoomHub.Broadcast")
+	defer span.End()
+	if input.Event == nil {
+		return nil, errors.New("event is required")
+	}
+	payload, err := encodeEnvelope(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	if _, err := h.bus.Publish(ctx, &port.PublishMessageBusInput{
+		Topic:   input.Event.RoomID,
+		Payload: payload,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &port.BroadcastRoomHubOutput{}, nil
+}
+
+// Shutdown stops accepting connections, then every connection is closed as going away
+// once the events queued for it are sent.
+// When ctx is done before that, the remaining connections are closed immediately and ctx.Err() is returned.
+func (h *RoomHub) Shutdown(ctx context.Context) error {
+	h.mux.Lock()
+	h.closed = true
+	h.mux.Unlock()
+	h.closeOnce.Do(func() {
+		close(h.closing)
+	})
+
+	drained := make(chan struct{})
+	go func() {
+		h.writers.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		// writers are stuck in Send, closing the connections unblocks them
+		for _, c := range h.clients() {
+			h.disconnect(ctx, c, port.CloseReasonGoingAway)
+		}
+		return ctx.Err()
+	}
+}
+
+// deliver enqueues the event received from the message bus to the local connections of the room.
+func (h *RoomHub) deliver(ctx context.Context, payload []byte) {
+	input, err := decodeEnvelope(payload)
+	if err != nil {
+		util.FromContext(ctx).Error(err, "failed to decode event")
+		return
+	}
+
+	if input.Event.Type == port.RoomEventTypeUserKicked {
+		h.kick(ctx, input.Event.RoomID, input.Event.UserID)
+		return
+	}
+
+	var slowConsumers []*client
+	h.mux.RLock()
+	if r, ok := h.rooms[input.Event.RoomID]; ok {
+		for id, c := range r.clients {
+			if id == input.ExcludeConnID {
+				continue
+			}
+			select {
+			case c.queue <- input.Event:
+			default:
+				slowConsumers = append(slowConsumers, c)
+			}
+		}
+	}
+	h.mux.RUnlock()
+
+	for _, c := range slowConsumers {
+		// the connection must not outlive its room even when the event cannot be queued
+		if input.Event.Type == port.RoomEventTypeRoomDeleted {
+			h.disconnect(ctx, c, port.CloseReasonRoomDeleted)
+			continue
+		}
+		h.handleSlowConsumer(ctx, c)
+	}
+}
+
+// kick closes the local connections of the user in the room without sending them the queued events.
+func (h *RoomHub) kick(ctx context.Context, roomID entity.ID, userID entity.ID) {
+	var kicked []*client
+	h.mux.RLock()
+	if r, ok := h.rooms[roomID]; ok {
+		for _, c := range r.clients {
+			if c.userID == userID {
+				kicked = append(kicked, c)
+			}
+		}
+	}
+	h.mux.RUnlock()
+
+	for _, c := range kicked {
+		h.disconnect(ctx, c, port.CloseReasonKicked)
+	}
+}
+
+func (h *RoomHub) handleSlowConsumer(ctx context.Context, c *client) {
+	logger := util.FromContext(ctx).
+		WithValues("roomID", c.roomID).
+		WithValues("connID", c.id)
+	switch h.conf.SlowConsumerPolicy {
+	case SlowConsumerPolicyDrop:
+		logger.Warn(nil, "queue is full, event was dropped")
+	default:
+		logger.Warn(nil, "queue is full, connection is closed")
+		h.conf.Metrics.IncSlowConsumersDropped(ctx)
+		h.disconnect(ctx, c, port.CloseReasonSlowConsumer)
+	}
+}
+
+func (h *RoomHub) disconnect(ctx context.Context, c *client, reason port.CloseReason) {
+	if removed := h.remove(ctx, c.roomID, c.id); removed == nil {
+		return
+	}
+	c.stop()
+	if err := c.conn.Close(ctx, reason); err != nil {
+		util.FromContext(ctx).Error(err, "failed to close connection", "roomID", c.roomID, "connID", c.id)
+	}
+}
+
+func (h *RoomHub) remove(ctx context.Context, roomID entity.ID, connID entity.ID) *client {
+	c, emptied := h.detach(roomID, connID)
+	if c != nil {
+		h.conf.Metrics.AddConnections(ctx, roomID, -1)
+	}
+	// unsubscribing outside the lock, the subscription may be delivering and waiting for it
+	if emptied != nil {
+		if err := emptied.subscription.Unsubscribe(ctx); err != nil {
+			util.FromContext(ctx).Error(err, "failed to unsubscribe room", "roomID", roomID)
+		}
+	}
+	return c
+}
+
+// detach removes the connection and returns the room as well when it has no connections left.
+func (h *RoomHub) detach(roomID entity.ID, connID entity.ID) (*client, *room) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	r, ok := h.rooms[roomID]
+	if !ok {
+		return nil, nil
+	}
+	c, ok := r.clients[connID]
+	if !ok {
+		return nil, nil
+	}
+	delete(r.clients, connID)
+	if len(r.clients) > 0 {
+		return c, nil
+	}
+	delete(h.rooms, roomID)
+	return c, r
+}
+
+func (h *RoomHub) clients() []*client {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	var clients []*client
+	for _, r := range h.rooms {
+		for _, c := range r.clients {
+			clients = append(clients, c)
+		}
+	}
+	return clients
+}
+
+func (h *RoomHub) write(ctx context.Context, c *client) {
+	defer h.writers.Done()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-h.closing:
+			h.drain(ctx, c)
+			return
+		case event := <-c.queue:
+			if err := c.conn.Send(ctx, event); err != nil {
+				util.FromContext(ctx).Error(err, "failed to send event", "roomID", c.roomID, "connID", c.id)
+				h.disconnect(ctx, c, port.CloseReasonNormal)
+				return
+			}
+			if event.Type == port.RoomEventTypeMessageCreated {
+				h.conf.Metrics.IncMessagesSent(ctx)
+			}
+			if event.Type == port.RoomEventTypeRoomDeleted {
+				h.disconnect(ctx, c, port.CloseReasonRoomDeleted)
+				return
+			}
+		}
+	}
+}
+
+// drain sends the queued events and closes the connection as going away.
+func (h *RoomHub) drain(ctx context.Context, c *client) {
+	for {
+		select {
+		case event := <-c.queue:
+			if err := c.conn.Send(ctx, event); err != nil {
+				util.FromContext(ctx).Error(err, "failed to send event", "roomID", c.roomID, "connID", c.id)
+				h.disconnect(ctx, c, port.CloseReasonGoingAway)
+				return
+			}
+			if event.Type == port.RoomEventTypeMessageCreated {
+				h.conf.Metrics.IncMessagesSent(ctx)
+			}
+			if event.Type == port.RoomEventTypeRoomDeleted {
+				h.disconnect(ctx, c, port.CloseReasonRoomDeleted)
+				return
+			}
+		default:
+			h.disconnect(ctx, c, port.CloseReasonGoingAway)
+			return
+		}
+	}
+}
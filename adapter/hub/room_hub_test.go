@@ -0,0 +1,400 @@
+package hub
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/mkaiho/go-ws-sample/adapter/bus"
+	"github.com/mkaiho/go-ws-sample/adapter/id"
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConn struct {
+	mux     sync.Mutex
+	block   chan struct{}
+	events  []*port.RoomEvent
+	closed  bool
+	reason  port.CloseReason
+	arrived chan struct{}
+}
+
+func newFakeConn(blocking bool) *fakeConn {
+	c := &fakeConn{
+		arrived: make(chan struct{}, 128),
+	}
+	if blocking {
+		c.block = make(chan struct{})
+	}
+	return c
+}
+
+func (c *fakeConn) Send(ctx context.Context, event *port.RoomEvent) error {
+	if c.block != nil {
+		<-c.block
+	}
+	c.mux.Lock()
+	c.events = append(c.events, event)
+	c.mux.Unlock()
+	c.arrived <- struct{}{}
+	return nil
+}
+
+func (c *fakeConn) Close(ctx context.Context, reason port.CloseReason) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.closed = true
+	c.reason = reason
+	return nil
+}
+
+func (c *fakeConn) wait(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-c.arrived:
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for event %d", i+1)
+		}
+	}
+}
+
+func TestRoomHub_Broadcast(t *testing.T) {
+	ctx := context.Background()
+	roomID := entity.ID("room")
+	otherRoomID := entity.ID("other")
+	h := NewRoomHub(id.NewULIDGenerator(), bus.NewInProcessBus())
+
+	sender := newFakeConn(false)
+	receiver := newFakeConn(false)
+	outsider := newFakeConn(false)
+	senderOut, err := h.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, Conn: sender})
+	require.NoError(t, err)
+	_, err = h.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, Conn: receiver})
+	require.NoError(t, err)
+	_, err = h.Join(ctx, &port.JoinRoomHubInput{RoomID: otherRoomID, Conn: outsider})
+	require.NoError(t, err)
+
+	_, err = h.Broadcast(ctx, &port.BroadcastRoomHubInput{
+		Event:         &port.RoomEvent{RoomID: roomID, Message: &entity.PostMessage{Body: "hello"}},
+		ExcludeConnID: senderOut.ConnID,
+	})
+	require.NoError(t, err)
+
+	receiver.wait(t, 1)
+	assert.Equal(t, "hello", receiver.events[0].Message.Body)
+	assert.Empty(t, sender.events)
+	assert.Empty(t, outsider.events)
+}
+
+func TestRoomHub_Broadcast_RoomDeleted(t *testing.T) {
+	ctx := context.Background()
+	roomID := entity.ID("room")
+	h := NewRoomHub(id.NewULIDGenerator(), bus.NewInProcessBus())
+
+	member := newFakeConn(false)
+	outsider := newFakeConn(false)
+	_, err := h.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, Conn: member})
+	require.NoError(t, err)
+	_, err = h.Join(ctx, &port.JoinRoomHubInput{RoomID: "other", Conn: outsider})
+	require.NoError(t, err)
+
+	_, err = h.Broadcast(ctx, &port.BroadcastRoomHubInput{
+		Event: &port.RoomEvent{Type: port.RoomEventTypeRoomDeleted, RoomID: roomID},
+	})
+	require.NoError(t, err)
+
+	member.wait(t, 1)
+	assert.Equal(t, port.RoomEventTypeRoomDeleted, member.events[0].Type)
+	assert.Eventually(t, func() bool {
+		member.mux.Lock()
+		defer member.mux.Unlock()
+		return member.closed && member.reason == port.CloseReasonRoomDeleted
+	}, time.Second, 10*time.Millisecond)
+	// the room was released before its last connection was closed
+	h.mux.RLock()
+	assert.NotContains(t, h.rooms, roomID)
+	assert.Contains(t, h.rooms, entity.ID("other"))
+	h.mux.RUnlock()
+}
+
+func TestRoomHub_Broadcast_UserKicked(t *testing.T) {
+	ctx := context.Background()
+	roomID := entity.ID("room")
+	h := NewRoomHub(id.NewULIDGenerator(), bus.NewInProcessBus())
+
+	kicked := newFakeConn(false)
+	other := newFakeConn(false)
+	elsewhere := newFakeConn(false)
+	_, err := h.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, UserID: "bob", Conn: kicked})
+	require.NoError(t, err)
+	_, err = h.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, UserID: "alice", Conn: other})
+	require.NoError(t, err)
+	_, err = h.Join(ctx, &port.JoinRoomHubInput{RoomID: "other", UserID: "bob", Conn: elsewhere})
+	require.NoError(t, err)
+
+	_, err = h.Broadcast(ctx, &port.BroadcastRoomHubInput{
+		Event: &port.RoomEvent{Type: port.RoomEventTypeUserKicked, RoomID: roomID, UserID: "bob"},
+	})
+	require.NoError(t, err)
+
+	kicked.mux.Lock()
+	assert.True(t, kicked.closed)
+	assert.Equal(t, port.CloseReasonKicked, kicked.reason)
+	kicked.mux.Unlock()
+	assert.False(t, other.closed)
+	assert.False(t, elsewhere.closed)
+	assert.Empty(t, other.events)
+}
+
+func TestRoomHub_Broadcast_AcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	roomID := entity.ID("room")
+	s := miniredis.RunT(t)
+	newHub := func() *RoomHub {
+		client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+		t.Cleanup(func() { client.Close() })
+		return NewRoomHub(id.NewULIDGenerator(), bus.NewRedisBus(client))
+	}
+	a := newHub()
+	b := newHub()
+
+	sender := newFakeConn(false)
+	local := newFakeConn(false)
+	remote := newFakeConn(false)
+	senderOut, err := a.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, Conn: sender})
+	require.NoError(t, err)
+	_, err = a.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, Conn: local})
+	require.NoError(t, err)
+	remoteOut, err := b.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, Conn: remote})
+	require.NoError(t, err)
+
+	_, err = a.Broadcast(ctx, &port.BroadcastRoomHubInput{
+		Event:         &port.RoomEvent{RoomID: roomID, Message: &entity.PostMessage{Body: "hello"}},
+		ExcludeConnID: senderOut.ConnID,
+	})
+	require.NoError(t, err)
+
+	local.wait(t, 1)
+	remote.wait(t, 1)
+	assert.Equal(t, "hello", remote.events[0].Message.Body)
+	assert.Empty(t, sender.events)
+
+	// the last connection of the room on b releases its subscription
+	_, err = b.Leave(ctx, &port.LeaveRoomHubInput{RoomID: roomID, ConnID: remoteOut.ConnID})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return s.PubSubNumSub("go-ws-sample:room")["go-ws-sample:room"] == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestRoomHub_SlowConsumer(t *testing.T) {
+	type args struct {
+		policy SlowConsumerPolicy
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantClosed bool
+	}{
+		{
+			name: "disconnect slow consumer",
+			args: args{
+				policy: SlowConsumerPolicyDisconnect,
+			},
+			wantClosed: true,
+		},
+		{
+			name: "drop events for slow consumer",
+			args: args{
+				policy: SlowConsumerPolicyDrop,
+			},
+			wantClosed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const queueSize = 2
+			ctx := context.Background()
+			roomID := entity.ID("room")
+			h := NewRoomHub(
+				id.NewULIDGenerator(),
+				bus.NewInProcessBus(),
+				OptionQueueSize(queueSize),
+				OptionSlowConsumerPolicy(tt.args.policy),
+			)
+			stalled := newFakeConn(true)
+			healthy := newFakeConn(false)
+			_, err := h.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, Conn: stalled})
+			require.NoError(t, err)
+			_, err = h.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, Conn: healthy})
+			require.NoError(t, err)
+
+			// one event is held by the stalled writer and the queue holds queueSize more
+			const count = queueSize + 3
+			for i := 0; i < count; i++ {
+				_, err := h.Broadcast(ctx, &port.BroadcastRoomHubInput{
+					Event: &port.RoomEvent{RoomID: roomID, Message: &entity.PostMessage{Body: "hello"}},
+				})
+				require.NoError(t, err)
+				healthy.wait(t, 1)
+			}
+
+			stalled.mux.Lock()
+			assert.Equal(t, tt.wantClosed, stalled.closed)
+			if tt.wantClosed {
+				assert.Equal(t, port.CloseReasonSlowConsumer, stalled.reason)
+			}
+			stalled.mux.Unlock()
+			close(stalled.block)
+		})
+	}
+}
+
+func TestRoomHub_Shutdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		blocking bool
+		timeout  time.Duration
+		wantErr  error
+	}{
+		{
+			name:    "close connections after queued events are sent",
+			timeout: time.Second,
+		},
+		{
+			name:     "close stalled connections when drain times out",
+			blocking: true,
+			timeout:  50 * time.Millisecond,
+			wantErr:  context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			roomID := entity.ID("room")
+			h := NewRoomHub(id.NewULIDGenerator(), bus.NewInProcessBus())
+			conn := newFakeConn(tt.blocking)
+			_, err := h.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, Conn: conn})
+			require.NoError(t, err)
+			for i := 0; i < 3; i++ {
+				_, err := h.Broadcast(ctx, &port.BroadcastRoomHubInput{
+					Event: &port.RoomEvent{RoomID: roomID, Message: &entity.PostMessage{Body: "hello"}},
+				})
+				require.NoError(t, err)
+			}
+
+			shutdownCtx, cancel := context.WithTimeout(ctx, tt.timeout)
+			defer cancel()
+			err = h.Shutdown(shutdownCtx)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				close(conn.block)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, conn.events, 3)
+			}
+			conn.mux.Lock()
+			assert.True(t, conn.closed)
+			assert.Equal(t, port.CloseReasonGoingAway, conn.reason)
+			conn.mux.Unlock()
+
+			_, err = h.Join(ctx, &port.JoinRoomHubInput{RoomID: roomID, Conn: newFakeConn(false)})
+			assert.ErrorIs(t, err, ErrRoomHubClosed)
+		})
+	}
+}
+
+// slowBus holds the subscriptions of a topic until release is closed.
+type slowBus struct {
+	port.MessageBus
+	topic      entity.ID
+	release    chan struct{}
+	err        error
+	subscribes chan entity.ID
+}
+
+func (b *slowBus) Subscribe(ctx context.Context, input *port.SubscribeMessageBusInput) (*port.SubscribeMessageBusOutput, error) {
+	b.subscribes <- input.Topic
+	if input.Topic == b.topic {
+		<-b.release
+		if b.err != nil {
+			return nil, b.err
+		}
+	}
+	return b.MessageBus.Subscribe(ctx, input)
+}
+
+func TestRoomHub_Join_Subscribe(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{
+			name: "join the room once it is subscribed",
+		},
+		{
+			name:    "return the subscription error and release the room",
+			err:     assert.AnError,
+			wantErr: assert.AnError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			slowRoomID := entity.ID("slow")
+			b := &slowBus{
+				MessageBus: bus.NewInProcessBus(),
+				topic:      slowRoomID,
+				release:    make(chan struct{}),
+				err:        tt.err,
+				subscribes: make(chan entity.ID, 8),
+			}
+			h := NewRoomHub(id.NewULIDGenerator(), b)
+
+			errs := make(chan error, 2)
+			for i := 0; i < 2; i++ {
+				go func() {
+					_, err := h.Join(ctx, &port.JoinRoomHubInput{RoomID: slowRoomID, Conn: newFakeConn(false)})
+					errs <- err
+				}()
+			}
+			assert.Equal(t, slowRoomID, <-b.subscribes)
+
+			// the other rooms are joined while the slow room is subscribing
+			done := make(chan error, 1)
+			go func() {
+				_, err := h.Join(ctx, &port.JoinRoomHubInput{RoomID: "other", Conn: newFakeConn(false)})
+				done <- err
+			}()
+			select {
+			case err := <-done:
+				require.NoError(t, err)
+			case <-time.After(time.Second):
+				t.Fatal("join of another room was blocked by the subscription")
+			}
+			assert.Equal(t, entity.ID("other"), <-b.subscribes)
+
+			close(b.release)
+			for i := 0; i < 2; i++ {
+				assert.ErrorIs(t, <-errs, tt.wantErr)
+			}
+			h.mux.RLock()
+			defer h.mux.RUnlock()
+			if tt.wantErr != nil {
+				assert.NotContains(t, h.rooms, slowRoomID)
+				return
+			}
+			// the concurrent joins of the slow room share its subscription
+			assert.Empty(t, b.subscribes)
+			assert.Len(t, h.rooms[slowRoomID].clients, 2)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+)
+
+var _ port.Metrics = NopMetrics{}
+
+// NopMetrics discards everything it records.
+type NopMetrics struct{}
+
+func (NopMetrics) ObserveHTTPRequest(ctx context.Context, method string, route string, status int, duration time.Duration) {
+}
+
+func (NopMetrics) AddConnections(ctx context.Context, roomID entity.ID, delta int) {}
+
+func (NopMetrics) IncMessagesReceived(ctx context.Context) {}
+
+func (NopMetrics) IncMessagesSent(ctx context.Context) {}
+
+func (NopMetrics) IncSlowConsumersDropped(ctx context.Context) {}
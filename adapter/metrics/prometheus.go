@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "go_ws_sample"
+
+var _ port.Metrics = (*PrometheusMetrics)(nil)
+
+// PrometheusMetrics records metrics into its own registry, which Handler exposes in the Prometheus text format.
+type PrometheusMetrics struct {
+	registry            *prometheus.Registry
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	connections         *prometheus.GaugeVec
+	messagesReceived    prometheus.Counter
+	messagesSent        prometheus.Counter
+	slowConsumers       prometheus.Counter
+
+	// mux guards connectionCounts, rooms are removed from the gauge when their last connection is closed.
+	mux              sync.Mutex
+	connectionCounts map[entity.ID]int
+}
+
+func NewPrometheusMetrics() *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of served HTTP requests.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of served HTTP requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		connections: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "websocket",
+			Name:      "connections",
+			Help:      "Number of open WebSocket connections.",
+		}, []string{"room_id"}),
+		messagesReceived: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "websocket",
+			Name:      "messages_received_total",
+			Help:      "Number of messages posted by clients.",
+		}),
+		messagesSent: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "websocket",
+			Name:      "messages_sent_total",
+			Help:      "Number of messages delivered to connections.",
+		}),
+		slowConsumers: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "websocket",
+			Name:      "slow_consumers_dropped_total",
+			Help:      "Number of connections closed because their queue was full.",
+		}),
+		connectionCounts: make(map[entity.ID]int),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.connections,
+		m.messagesReceived,
+		m.messagesSent,
+		m.slowConsumers,
+	)
+	return m
+}
+
+// Handler serves the recorded metrics.
+func (m *PrometheusMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *PrometheusMetrics) ObserveHTTPRequest(ctx context.Context, method string, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpRequestDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func (m *PrometheusMetrics) AddConnections(ctx context.Context, roomID entity.ID, delta int) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	count := m.connectionCounts[roomID] + delta
+	if count <= 0 {
+		delete(m.connectionCounts, roomID)
+		m.connections.DeleteLabelValues(roomID.String())
+		return
+	}
+	m.connectionCounts[roomID] = count
+	m.connections.WithLabelValues(roomID.String()).Set(float64(count))
+}
+
+func (m *PrometheusMetrics) IncMessagesReceived(ctx context.Context) {
+	m.messagesReceived.Inc()
+}
+
+func (m *PrometheusMetrics) IncMessagesSent(ctx context.Context) {
+	m.messagesSent.Inc()
+}
+
+func (m *PrometheusMetrics) IncSlowConsumersDropped(ctx context.Context) {
+	m.slowConsumers.Inc()
+}
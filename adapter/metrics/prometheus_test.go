@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	ctx := context.Background()
+	m := NewPrometheusMetrics()
+
+	m.ObserveHTTPRequest(ctx, "GET", "/rooms/:room_id", 200, 10*time.Millisecond)
+	m.ObserveHTTPRequest(ctx, "GET", "/rooms/:room_id", 200, 20*time.Millisecond)
+	m.AddConnections(ctx, "room", 1)
+	m.AddConnections(ctx, "room", 1)
+	m.AddConnections(ctx, "other", 1)
+	m.AddConnections(ctx, "other", -1)
+	m.IncMessagesReceived(ctx)
+	m.IncMessagesSent(ctx)
+	m.IncMessagesSent(ctx)
+	m.IncSlowConsumersDropped(ctx)
+
+	err := testutil.GatherAndCompare(m.registry, strings.NewReader(`
+# HELP go_ws_sample_http_requests_total Number of served HTTP requests.
+# TYPE go_ws_sample_http_requests_total counter
+go_ws_sample_http_requests_total{method="GET",route="/rooms/:room_id",status="200"} 2
+# HELP go_ws_sample_websocket_connections Number of open WebSocket connections.
+# TYPE go_ws_sample_websocket_connections gauge
+go_ws_sample_websocket_connections{room_id="room"} 2
+# HELP go_ws_sample_websocket_messages_received_total Number of messages posted by clients.
+# TYPE go_ws_sample_websocket_messages_received_total counter
+go_ws_sample_websocket_messages_received_total 1
+# HELP go_ws_sample_websocket_messages_sent_total Number of messages delivered to connections.
+# TYPE go_ws_sample_websocket_messages_sent_total counter
+go_ws_sample_websocket_messages_sent_total 2
+# HELP go_ws_sample_websocket_slow_consumers_dropped_total Number of connections closed because their queue was full.
+# TYPE go_ws_sample_websocket_slow_consumers_dropped_total counter
+go_ws_sample_websocket_slow_consumers_dropped_total 1
+`),
+		"go_ws_sample_http_requests_total",
+		"go_ws_sample_websocket_connections",
+		"go_ws_sample_websocket_messages_received_total",
+		"go_ws_sample_websocket_messages_sent_total",
+		"go_ws_sample_websocket_slow_consumers_dropped_total",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1, testutil.CollectAndCount(m.httpRequestDuration))
+}
@@ -0,0 +1,44 @@
+package password
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var _ (port.PasswordHasher) = (*BcryptHasher)(nil)
+
+const DefaultCost = bcrypt.DefaultCost
+
+type BcryptHasher struct {
+	cost int
+}
+
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = DefaultCost
+	}
+	return &BcryptHasher{
+		cost: cost,
+	}
+}
+
+func (h *BcryptHasher) Hash(ctx context.Context, password string) ([]byte, error) {
+	_, span := util.StartSpan(ctx, "password.BcryptHasher.Hash")
+	defer span.End()
+	return bcrypt.GenerateFromPassword([]byte(password), h.cost)
+}
+
+func (h *BcryptHasher) Compare(ctx context.Context, hash []byte, password string) error {
+	_, span := util.StartSpan(ctx, "password.BcryptHasher.Compare")
+	defer span.End()
+	err := bcrypt.CompareHashAndPassword(hash, []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return usecase.ErrInvalidCredential
+	}
+	return err
+}
@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/mkaiho/go-ws-sample/adapter/sqldb"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+)
+
+// The stores are shared with the other databases, they run on postgres through its Dialect.
+type (
+	RoomsAccess     = sqldb.RoomsAccess
+	MessagesAccess  = sqldb.MessagesAccess
+	UsersAccess     = sqldb.UsersAccess
+	SanctionsAccess = sqldb.SanctionsAccess
+)
+
+func NewRoomsAccess(db *sql.DB, idGenerator port.IDGenerator) *RoomsAccess {
+	return sqldb.NewRoomsAccess(db, Dialect, idGenerator)
+}
+
+func NewMessagesAccess(db *sql.DB, idGenerator port.IDGenerator) *MessagesAccess {
+	return sqldb.NewMessagesAccess(db, Dialect, idGenerator)
+}
+
+func NewUsersAccess(db *sql.DB, idGenerator port.IDGenerator) *UsersAccess {
+	return sqldb.NewUsersAccess(db, Dialect, idGenerator)
+}
+
+func NewSanctionsAccess(db *sql.DB) *SanctionsAccess {
+	return sqldb.NewSanctionsAccess(db, Dialect)
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/adapter/sqldb"
+)
+
+// Dialect runs the stores and the migrations of sqldb on postgres.
+var Dialect sqldb.Dialect = dialect{}
+
+var _ sqldb.MigrationLocker = dialect{}
+
+// migrationLockKey identifies the advisory lock held while migrating,
+// so replicas starting at the same time do not apply a migration twice.
+const migrationLockKey = 0x67777373
+
+type dialect struct{}
+
+func (dialect) Name() string {
+	return "postgres"
+}
+
+// Rebind numbers the ? placeholders as $1, $2 and so on, the queries hold no ? in literals.
+func (dialect) Rebind(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteByte('$')
+		b.WriteString(strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+func (dialect) TimestampType() string {
+	return "TIMESTAMPTZ"
+}
+
+// LockMigrations holds a session advisory lock while migrating.
+func (dialect) LockMigrations(ctx context.Context, conn *sql.Conn) (func(), error) {
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return nil, err
+	}
+	return func() {
+		conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+	}, nil
+}
+
+// TimeValue passes t as it is to a TIMESTAMPTZ column.
+func (dialect) TimeValue(t time.Time) any {
+	return t
+}
+
+func (dialect) ScanTime(src any) (time.Time, error) {
+	t, ok := src.(time.Time)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected time value %T", src)
+	}
+	return t, nil
+}
+
+// MemberOrder breaks the ties of the members added in the same transaction by their id.
+func (dialect) MemberOrder() string {
+	return "m.joined_at, m.user_id"
+}
+
+func (dialect) TranslateError(err error) error {
+	return translateError(err)
+}
@@ -0,0 +1,17 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+
+	"github.com/mkaiho/go-ws-sample/adapter/sqldb"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate applies the embedded migrations newer than the recorded schema version.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	return sqldb.Migrate(ctx, db, Dialect, migrations)
+}
@@ -0,0 +1,9 @@
+CREATE TABLE messages (
+    id        TEXT PRIMARY KEY,
+    room_id   TEXT NOT NULL REFERENCES rooms (id) ON DELETE CASCADE,
+    body      TEXT NOT NULL,
+    posted_at TIMESTAMPTZ NOT NULL,
+    posted_by TEXT REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX messages_room_id_id ON messages (room_id, id);
@@ -0,0 +1,10 @@
+ALTER TABLE room_members ADD COLUMN role TEXT NOT NULL DEFAULT 'member';
+
+-- Rooms created before roles existed did not record their creator,
+-- the earliest member of each room becomes its owner.
+UPDATE room_members SET role = 'owner'
+WHERE (room_id, user_id) IN (
+    SELECT DISTINCT ON (room_id) room_id, user_id
+    FROM room_members
+    ORDER BY room_id, joined_at, user_id
+);
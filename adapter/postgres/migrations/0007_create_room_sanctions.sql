@@ -0,0 +1,7 @@
+CREATE TABLE room_sanctions (
+    room_id    TEXT NOT NULL REFERENCES rooms (id) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type       TEXT NOT NULL,
+    expires_at TIMESTAMPTZ, -- never expires when NULL
+    PRIMARY KEY (room_id, user_id, type)
+);
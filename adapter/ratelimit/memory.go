@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ port.RateLimiter = (*InMemoryRateLimiter)(nil)
+
+// sweepInterval is how often the buckets refilled to their burst are released.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   port.RateLimit
+}
+
+// refill adds the tokens accumulated since the last update.
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+	b.updated = now
+}
+
+// InMemoryRateLimiter keeps token buckets in the process, so every instance limits on its own.
+type InMemoryRateLimiter struct {
+	mux       sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewInMemoryRateLimiter() *InMemoryRateLimiter {
+	return &InMemoryRateLimiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (l *InMemoryRateLimiter) Allow(ctx context.Context, input *port.AllowRateInput) (*port.AllowRateOutput, error) {
+	_, span := util.StartSpan(ctx, "ratelimit.InMemoryRateLimiter.Allow")
+	defer span.End()
+	now := l.now()
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.sweep(now)
+
+	b, ok := l.buckets[input.Key]
+	if !ok {
+		b = &bucket{
+			tokens:  float64(input.Limit.Burst),
+			updated: now,
+		}
+		l.buckets[input.Key] = b
+	}
+	b.limit = input.Limit
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return &port.AllowRateOutput{
+			Allowed: true,
+		}, nil
+	}
+	return &port.AllowRateOutput{
+		RetryAfter: time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second)),
+	}, nil
+}
+
+// sweep releases the buckets which are full, taking from them again is the same as from a new one.
+func (l *InMemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.refill(now); b.tokens >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
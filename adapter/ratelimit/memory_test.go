@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryRateLimiter_Allow(t *testing.T) {
+	limit := port.RateLimit{Rate: 2, Burst: 3}
+	start := time.Date(2024, 2, 8, 0, 0, 0, 0, time.UTC)
+	type call struct {
+		key     string
+		elapsed time.Duration
+	}
+	tests := []struct {
+		name  string
+		calls []call
+		want  *port.AllowRateOutput
+	}{
+		{
+			name:  "allow events up to the burst",
+			calls: []call{{key: "a"}, {key: "a"}},
+			want:  &port.AllowRateOutput{Allowed: true},
+		},
+		{
+			name:  "deny events beyond the burst until a token is refilled",
+			calls: []call{{key: "a"}, {key: "a"}, {key: "a"}, {key: "a", elapsed: 100 * time.Millisecond}},
+			want:  &port.AllowRateOutput{RetryAfter: 400 * time.Millisecond},
+		},
+		{
+			name:  "allow events again after a token is refilled",
+			calls: []call{{key: "a"}, {key: "a"}, {key: "a"}, {key: "a", elapsed: 500 * time.Millisecond}},
+			want:  &port.AllowRateOutput{Allowed: true},
+		},
+		{
+			name:  "limit each key on its own",
+			calls: []call{{key: "a"}, {key: "a"}, {key: "a"}, {key: "b"}},
+			want:  &port.AllowRateOutput{Allowed: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			now := start
+			l := NewInMemoryRateLimiter()
+			l.lastSweep = start
+			l.now = func() time.Time { return now }
+
+			var got *port.AllowRateOutput
+			for _, c := range tt.calls {
+				now = now.Add(c.elapsed)
+				var err error
+				got, err = l.Allow(ctx, &port.AllowRateInput{Key: c.key, Limit: limit})
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("release full buckets after the sweep interval", func(t *testing.T) {
+		ctx := context.Background()
+		now := start
+		l := NewInMemoryRateLimiter()
+		l.lastSweep = start
+		l.now = func() time.Time { return now }
+		_, err := l.Allow(ctx, &port.AllowRateInput{Key: "a", Limit: limit})
+		require.NoError(t, err)
+
+		now = now.Add(sweepInterval)
+		_, err = l.Allow(ctx, &port.AllowRateInput{Key: "b", Limit: limit})
+		require.NoError(t, err)
+		assert.NotContains(t, l.buckets, "a")
+		assert.Contains(t, l.buckets, "b")
+	})
+}
@@ -0,0 +1,53 @@
+// Package sqldb implements the stores on database/sql for the databases described by a Dialect.
+// The queries are written with ? placeholders in the SQL understood by both sqlite and postgres.
+package sqldb
+
+import (
+	"fmt"
+	"time"
+)
+
+// Dialect is what differs between the databases behind the stores.
+type Dialect interface {
+	// Name prefixes the names of the spans, such as "sqlite".
+	Name() string
+	// Rebind rewrites the ? placeholders of query into the ones of the database.
+	Rebind(query string) string
+	// TimeValue converts t into the argument stored in a time column.
+	TimeValue(t time.Time) any
+	// ScanTime converts the non NULL value scanned from a time column back into a time.
+	ScanTime(src any) (time.Time, error)
+	// TimestampType is the column type of the times set by the database, such as when a migration was applied.
+	TimestampType() string
+	// MemberOrder is the ORDER BY expression listing the rows of room_members m in the order they were added.
+	MemberOrder() string
+	// TranslateError maps constraint violations to usecase errors.
+	TranslateError(err error) error
+}
+
+// timeColumn scans a nullable time column with the conversion of the dialect.
+type timeColumn struct {
+	dialect Dialect
+	time    *time.Time
+}
+
+func (c *timeColumn) Scan(src any) error {
+	if src == nil {
+		c.time = nil
+		return nil
+	}
+	t, err := c.dialect.ScanTime(src)
+	if err != nil {
+		return fmt.Errorf("failed to scan time: %w", err)
+	}
+	c.time = &t
+	return nil
+}
+
+// timeValue is TimeValue of the dialect passing a nil time as NULL.
+func timeValue(dialect Dialect, t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return dialect.TimeValue(*t)
+}
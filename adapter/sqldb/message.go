@@ -0,0 +1,183 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var (
+	_ port.MessagesReader = (*MessagesAccess)(nil)
+	_ port.MessagesWriter = (*MessagesAccess)(nil)
+)
+
+type MessagesAccess struct {
+	db          *sql.DB
+	dialect     Dialect
+	idGenerator port.IDGenerator
+}
+
+func NewMessagesAccess(db *sql.DB, dialect Dialect, idGenerator port.IDGenerator) *MessagesAccess {
+	return &MessagesAccess{
+		db:          db,
+		dialect:     dialect,
+		idGenerator: idGenerator,
+	}
+}
+
+func (a *MessagesAccess) Find(ctx context.Context, input *port.FindMessagesInput) (*port.FindMessagesOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".MessagesAccess.Find")
+	defer span.End()
+	order := "DESC"
+	if input.After != nil {
+		order = "ASC"
+	}
+	query := fmt.Sprintf(`
+		SELECT m.id, m.room_id, m.body, m.posted_at, u.id, u.name
+		FROM messages m
+		LEFT JOIN users u ON u.id = m.posted_by
+		WHERE m.room_id = ? AND (CAST(? AS TEXT) IS NULL OR m.id < ?) AND (CAST(? AS TEXT) IS NULL OR m.id > ?)
+		ORDER BY m.id %s`, order)
+	args := []any{input.RoomID, input.Before, input.Before, input.After, input.After}
+	if input.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, input.Limit)
+	}
+	rows, err := a.db.QueryContext(ctx, a.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := entity.PostMessages{}
+	for rows.Next() {
+		message, err := a.scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find messages: %w", err)
+	}
+
+	return &port.FindMessagesOutput{
+		Messages: messages,
+	}, nil
+}
+
+func (a *MessagesAccess) Create(ctx context.Context, input *port.CreateMessageInput) (*port.CreateMessageOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".MessagesAccess.Create")
+	defer span.End()
+	id, err := a.idGenerator.Generate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate id: %w", err)
+	}
+	// times are stored in microseconds, truncate so the returned message matches the stored one
+	now := time.Now().Truncate(time.Microsecond)
+	message := entity.PostMessage{
+		ID:             id,
+		RoomID:         input.RoomID,
+		Body:           input.Body,
+		PostedDatetime: &now,
+		PostedBy:       input.PostedBy,
+	}
+	var postedBy *entity.ID
+	if input.PostedBy != nil {
+		postedBy = &input.PostedBy.ID
+	}
+	var key *string
+	if len(input.IdempotencyKey) > 0 && postedBy != nil {
+		key = &input.IdempotencyKey
+	}
+	res, err := a.db.ExecContext(ctx, a.dialect.Rebind(`
+		INSERT INTO messages (id, room_id, body, posted_at, posted_by, idempotency_key)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (room_id, posted_by, idempotency_key) WHERE idempotency_key IS NOT NULL DO NOTHING`),
+		message.ID, message.RoomID, message.Body, a.dialect.TimeValue(now), postedBy, key,
+	)
+	if err != nil {
+		return nil, a.dialect.TranslateError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return a.findByIdempotencyKey(ctx, message.RoomID, *postedBy, *key)
+	}
+
+	return &port.CreateMessageOutput{
+		Message: &message,
+		Created: true,
+	}, nil
+}
+
+func (a *MessagesAccess) findByIdempotencyKey(ctx context.Context, roomID entity.ID, userID entity.ID, key string) (*port.CreateMessageOutput, error) {
+	row := a.db.QueryRowContext(ctx, a.dialect.Rebind(`
+		SELECT m.id, m.room_id, m.body, m.posted_at, u.id, u.name
+		FROM messages m
+		LEFT JOIN users u ON u.id = m.posted_by
+		WHERE m.room_id = ? AND m.posted_by = ? AND m.idempotency_key = ?`),
+		roomID, userID, key,
+	)
+	message, err := a.scanMessage(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return &port.CreateMessageOutput{
+		Message: message,
+		Created: false,
+	}, nil
+}
+
+func (a *MessagesAccess) scanMessage(rows interface{ Scan(dest ...any) error }) (*entity.PostMessage, error) {
+	var (
+		message      entity.PostMessage
+		postedByID   sql.NullString
+		postedByName sql.NullString
+	)
+	postedAt := timeColumn{dialect: a.dialect}
+	if err := rows.Scan(&message.ID, &message.RoomID, &message.Body, &postedAt, &postedByID, &postedByName); err != nil {
+		return nil, fmt.Errorf("failed to scan message: %w", err)
+	}
+	message.PostedDatetime = postedAt.time
+	if postedByID.Valid {
+		message.PostedBy = &entity.User{
+			ID:   entity.ID(postedByID.String),
+			Name: postedByName.String,
+		}
+	}
+	return &message, nil
+}
+
+func (a *MessagesAccess) Delete(ctx context.Context, input *port.DeleteMessagesInput) (*port.DeleteMessagesOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".MessagesAccess.Delete")
+	defer span.End()
+	if input.MessageID == nil {
+		if _, err := a.db.ExecContext(ctx, a.dialect.Rebind(`DELETE FROM messages WHERE room_id = ?`), input.RoomID); err != nil {
+			return nil, a.dialect.TranslateError(err)
+		}
+		return &port.DeleteMessagesOutput{}, nil
+	}
+
+	res, err := a.db.ExecContext(ctx,
+		a.dialect.Rebind(`DELETE FROM messages WHERE room_id = ? AND id = ?`),
+		input.RoomID, *input.MessageID,
+	)
+	if err != nil {
+		return nil, a.dialect.TranslateError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, usecase.ErrNotFoundEntity
+	}
+
+	return &port.DeleteMessagesOutput{}, nil
+}
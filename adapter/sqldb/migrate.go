@@ -0,0 +1,118 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MigrationLocker is implemented by the dialects whose databases are migrated by replicas starting at the same time.
+type MigrationLocker interface {
+	// LockMigrations holds a lock on conn until unlock is called, so a migration is not applied twice.
+	LockMigrations(ctx context.Context, conn *sql.Conn) (unlock func(), err error)
+}
+
+type Migration struct {
+	Version int
+	Name    string
+}
+
+// Migrate applies the migrations of the migrations directory of files newer than the recorded schema version.
+// Each migration file is named "<version>_<description>.sql" and runs in its own transaction.
+func Migrate(ctx context.Context, db *sql.DB, dialect Dialect, files fs.FS) error {
+	// a session lock must be released by the connection holding it, so every statement uses the same one
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if locker, ok := dialect.(MigrationLocker); ok {
+		unlock, err := locker.LockMigrations(ctx, conn)
+		if err != nil {
+			return fmt.Errorf("failed to lock migrations: %w", err)
+		}
+		defer unlock()
+	}
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			applied_at %s NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`, dialect.TimestampType()),
+	); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := conn.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	list, err := ListMigrations(files)
+	if err != nil {
+		return err
+	}
+	for _, m := range list {
+		if m.Version <= current {
+			continue
+		}
+		if err := apply(ctx, conn, dialect, files, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListMigrations returns the migrations of the migrations directory of files ordered by version.
+func ListMigrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	var list []Migration
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration name: %s", entry.Name())
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration name: %s", entry.Name())
+		}
+		list = append(list, Migration{
+			Version: version,
+			Name:    entry.Name(),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+	return list, nil
+}
+
+func apply(ctx context.Context, conn *sql.Conn, dialect Dialect, files fs.FS, m Migration) (err error) {
+	query, err := fs.ReadFile(files, "migrations/"+m.Name)
+	if err != nil {
+		return fmt.Errorf("failed to read migration %s: %w", m.Name, err)
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if _, err = tx.ExecContext(ctx, string(query)); err != nil {
+		return fmt.Errorf("failed to apply migration %s: %w", m.Name, err)
+	}
+	if _, err = tx.ExecContext(ctx, dialect.Rebind(`INSERT INTO schema_migrations (version) VALUES (?)`), m.Version); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", m.Name, err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,338 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+// likeEscaper escapes the wildcards of a LIKE pattern with \.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+var (
+	_ port.RoomsReader = (*RoomsAccess)(nil)
+	_ port.RoomsWriter = (*RoomsAccess)(nil)
+)
+
+type RoomsAccess struct {
+	db          *sql.DB
+	dialect     Dialect
+	idGenerator port.IDGenerator
+}
+
+func NewRoomsAccess(db *sql.DB, dialect Dialect, idGenerator port.IDGenerator) *RoomsAccess {
+	return &RoomsAccess{
+		db:          db,
+		dialect:     dialect,
+		idGenerator: idGenerator,
+	}
+}
+
+func (a *RoomsAccess) Find(ctx context.Context, input *port.FindRoomsInput) (*port.FindRoomsOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".RoomsAccess.Find")
+	defer span.End()
+	orderBy, after := "id", "id > ?"
+	if input.Sort == port.RoomsSortKeyName {
+		orderBy, after = "name, id", "(name, id) > (?, ?)"
+	}
+	where := `(? = '' OR lower(name) LIKE '%' || lower(CAST(? AS TEXT)) || '%' ESCAPE '\')`
+	args := []any{input.Query, likeEscaper.Replace(input.Query)}
+	if input.After != nil {
+		where += " AND " + after
+		if input.Sort == port.RoomsSortKeyName {
+			args = append(args, input.After.Name)
+		}
+		args = append(args, input.After.ID)
+	}
+	query := fmt.Sprintf(`
+		SELECT id, name, description, version
+		FROM rooms
+		WHERE %s
+		ORDER BY %s`, where, orderBy)
+	if input.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, input.Limit)
+	}
+	rows, err := a.db.QueryContext(ctx, a.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rooms: %w", err)
+	}
+	defer rows.Close()
+
+	rooms := entity.Rooms{}
+	for rows.Next() {
+		var room entity.Room
+		if err := rows.Scan(&room.ID, &room.Name, &room.Description, &room.Version); err != nil {
+			return nil, fmt.Errorf("failed to scan room: %w", err)
+		}
+		rooms = append(rooms, &room)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find rooms: %w", err)
+	}
+
+	return &port.FindRoomsOutput{
+		Rooms: rooms,
+	}, nil
+}
+
+func (a *RoomsAccess) Get(ctx context.Context, input *port.GetRoomInput) (*port.GetRoomOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".RoomsAccess.Get")
+	defer span.End()
+	var room entity.Room
+	err := a.db.QueryRowContext(ctx, a.dialect.Rebind(`SELECT id, name, description, version FROM rooms WHERE id = ?`), input.ID).
+		Scan(&room.ID, &room.Name, &room.Description, &room.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+	room.Members, err = a.findMembers(ctx, room.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &port.GetRoomOutput{
+		Room: &room,
+	}, nil
+}
+
+func (a *RoomsAccess) Create(ctx context.Context, input *port.CreateRoomInput) (_ *port.CreateRoomOutput, err error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".RoomsAccess.Create")
+	defer span.End()
+	id, err := a.idGenerator.Generate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate id: %w", err)
+	}
+	room := entity.Room{
+		ID:          id,
+		Name:        input.Name,
+		Description: input.Description,
+		Version:     1,
+	}
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if _, err = tx.ExecContext(ctx,
+		a.dialect.Rebind(`INSERT INTO rooms (id, name, description) VALUES (?, ?, ?)`),
+		room.ID, room.Name, room.Description,
+	); err != nil {
+		return nil, a.dialect.TranslateError(err)
+	}
+	room.Members = entity.Members{}
+	if input.Owner != nil {
+		owner := *input.Owner
+		if _, err = tx.ExecContext(ctx,
+			a.dialect.Rebind(`INSERT INTO room_members (room_id, user_id, role) VALUES (?, ?, ?)`),
+			room.ID, owner.ID, entity.RoleOwner,
+		); err != nil {
+			return nil, a.dialect.TranslateError(err)
+		}
+		room.Members = append(room.Members, &entity.Member{
+			User: &owner,
+			Role: entity.RoleOwner,
+		})
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, a.dialect.TranslateError(err)
+	}
+
+	return &port.CreateRoomOutput{
+		Room: &room,
+	}, nil
+}
+
+func (a *RoomsAccess) Update(ctx context.Context, input *port.UpdateRoomInput) (*port.UpdateRoomOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".RoomsAccess.Update")
+	defer span.End()
+	var description *string
+	if input.Description != nil {
+		description = *input.Description
+	}
+	var room entity.Room
+	err := a.db.QueryRowContext(ctx, a.dialect.Rebind(`
+		UPDATE rooms SET
+			name = COALESCE(CAST(? AS TEXT), name),
+			description = CASE WHEN CAST(? AS BOOLEAN) THEN CAST(? AS TEXT) ELSE description END,
+			version = version + 1
+		WHERE id = ? AND (CAST(? AS INTEGER) IS NULL OR version = ?)
+		RETURNING id, name, description, version`),
+		input.Name, input.Description != nil, description, input.ID, input.Version, input.Version,
+	).Scan(&room.ID, &room.Name, &room.Description, &room.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, a.versionConflict(ctx, input.ID)
+	}
+	if err != nil {
+		return nil, a.dialect.TranslateError(err)
+	}
+	room.Members, err = a.findMembers(ctx, room.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &port.UpdateRoomOutput{
+		Room: &room,
+	}, nil
+}
+
+func (a *RoomsAccess) Delete(ctx context.Context, input *port.DeleteRoomInput) (*port.DeleteRoomOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".RoomsAccess.Delete")
+	defer span.End()
+	res, err := a.db.ExecContext(ctx,
+		a.dialect.Rebind(`DELETE FROM rooms WHERE id = ? AND (CAST(? AS INTEGER) IS NULL OR version = ?)`),
+		input.ID, input.Version, input.Version,
+	)
+	if err != nil {
+		return nil, a.dialect.TranslateError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, a.versionConflict(ctx, input.ID)
+	}
+
+	return &port.DeleteRoomOutput{}, nil
+}
+
+func (a *RoomsAccess) FindMembers(ctx context.Context, input *port.FindRoomMembersInput) (*port.FindRoomMembersOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".RoomsAccess.FindMembers")
+	defer span.End()
+	if err := a.exists(ctx, input.RoomID); err != nil {
+		return nil, err
+	}
+	members, err := a.findMembers(ctx, input.RoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &port.FindRoomMembersOutput{
+		Members: members,
+	}, nil
+}
+
+func (a *RoomsAccess) GetMember(ctx context.Context, input *port.GetRoomMemberInput) (*port.GetRoomMemberOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".RoomsAccess.GetMember")
+	defer span.End()
+	member := entity.Member{
+		User: &entity.User{},
+	}
+	err := a.db.QueryRowContext(ctx, a.dialect.Rebind(`
+		SELECT u.id, u.name, m.role
+		FROM room_members m
+		INNER JOIN users u ON u.id = m.user_id
+		WHERE m.room_id = ? AND m.user_id = ?`),
+		input.RoomID, input.UserID,
+	).Scan(&member.User.ID, &member.User.Name, &member.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member: %w", err)
+	}
+
+	return &port.GetRoomMemberOutput{
+		Member: &member,
+	}, nil
+}
+
+func (a *RoomsAccess) AddMember(ctx context.Context, input *port.AddRoomMemberInput) (*port.AddRoomMemberOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".RoomsAccess.AddMember")
+	defer span.End()
+	if _, err := a.db.ExecContext(ctx,
+		a.dialect.Rebind(`INSERT INTO room_members (room_id, user_id, role) VALUES (?, ?, ?)`),
+		input.RoomID, input.User.ID, input.Role,
+	); err != nil {
+		return nil, a.dialect.TranslateError(err)
+	}
+
+	user := *input.User
+	return &port.AddRoomMemberOutput{
+		Member: &entity.Member{
+			User: &user,
+			Role: input.Role,
+		},
+	}, nil
+}
+
+func (a *RoomsAccess) RemoveMember(ctx context.Context, input *port.RemoveRoomMemberInput) (*port.RemoveRoomMemberOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".RoomsAccess.RemoveMember")
+	defer span.End()
+	res, err := a.db.ExecContext(ctx,
+		a.dialect.Rebind(`DELETE FROM room_members WHERE room_id = ? AND user_id = ?`),
+		input.RoomID, input.UserID,
+	)
+	if err != nil {
+		return nil, a.dialect.TranslateError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, usecase.ErrNotFoundEntity
+	}
+
+	return &port.RemoveRoomMemberOutput{}, nil
+}
+
+func (a *RoomsAccess) exists(ctx context.Context, roomID entity.ID) error {
+	var id entity.ID
+	err := a.db.QueryRowContext(ctx, a.dialect.Rebind(`SELECT id FROM rooms WHERE id = ?`), roomID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return usecase.ErrNotFoundEntity
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get room: %w", err)
+	}
+	return nil
+}
+
+// versionConflict tells why a conditional write of the room affected no rows.
+func (a *RoomsAccess) versionConflict(ctx context.Context, roomID entity.ID) error {
+	if err := a.exists(ctx, roomID); err != nil {
+		return err
+	}
+	return usecase.ErrVersionConflict
+}
+
+func (a *RoomsAccess) findMembers(ctx context.Context, roomID entity.ID) (entity.Members, error) {
+	rows, err := a.db.QueryContext(ctx, a.dialect.Rebind(fmt.Sprintf(`
+		SELECT u.id, u.name, m.role
+		FROM room_members m
+		INNER JOIN users u ON u.id = m.user_id
+		WHERE m.room_id = ?
+		ORDER BY %s`, a.dialect.MemberOrder())),
+		roomID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find members: %w", err)
+	}
+	defer rows.Close()
+
+	members := entity.Members{}
+	for rows.Next() {
+		member := entity.Member{
+			User: &entity.User{},
+		}
+		if err := rows.Scan(&member.User.ID, &member.User.Name, &member.Role); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		members = append(members, &member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find members: %w", err)
+	}
+	return members, nil
+}
@@ -0,0 +1,98 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var (
+	_ port.SanctionsReader = (*SanctionsAccess)(nil)
+	_ port.SanctionsWriter = (*SanctionsAccess)(nil)
+)
+
+type SanctionsAccess struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+func NewSanctionsAccess(db *sql.DB, dialect Dialect) *SanctionsAccess {
+	return &SanctionsAccess{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+func (a *SanctionsAccess) Get(ctx context.Context, input *port.GetSanctionInput) (*port.GetSanctionOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".SanctionsAccess.Get")
+	defer span.End()
+	expiresAt := timeColumn{dialect: a.dialect}
+	err := a.db.QueryRowContext(ctx, a.dialect.Rebind(`
+		SELECT expires_at
+		FROM room_sanctions
+		WHERE room_id = ? AND user_id = ? AND type = ? AND (expires_at IS NULL OR expires_at > ?)`),
+		input.RoomID, input.UserID, input.Type, a.dialect.TimeValue(time.Now()),
+	).Scan(&expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sanction: %w", err)
+	}
+
+	return &port.GetSanctionOutput{
+		Sanction: &entity.Sanction{
+			RoomID:    input.RoomID,
+			UserID:    input.UserID,
+			Type:      input.Type,
+			ExpiresAt: expiresAt.time,
+		},
+	}, nil
+}
+
+func (a *SanctionsAccess) Put(ctx context.Context, input *port.PutSanctionInput) (*port.PutSanctionOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".SanctionsAccess.Put")
+	defer span.End()
+	sanction := *input.Sanction
+	if sanction.ExpiresAt != nil {
+		// times are stored in microseconds, truncate so the returned sanction matches the stored one
+		sanction.ExpiresAt = util.ToPointer(sanction.ExpiresAt.Truncate(time.Microsecond))
+	}
+	if _, err := a.db.ExecContext(ctx, a.dialect.Rebind(`
+		INSERT INTO room_sanctions (room_id, user_id, type, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (room_id, user_id, type) DO UPDATE SET expires_at = excluded.expires_at`),
+		sanction.RoomID, sanction.UserID, sanction.Type, timeValue(a.dialect, sanction.ExpiresAt),
+	); err != nil {
+		return nil, a.dialect.TranslateError(err)
+	}
+
+	return &port.PutSanctionOutput{
+		Sanction: &sanction,
+	}, nil
+}
+
+func (a *SanctionsAccess) Delete(ctx context.Context, input *port.DeleteSanctionInput) (*port.DeleteSanctionOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".SanctionsAccess.Delete")
+	defer span.End()
+	res, err := a.db.ExecContext(ctx,
+		a.dialect.Rebind(`DELETE FROM room_sanctions WHERE room_id = ? AND user_id = ? AND type = ?`),
+		input.RoomID, input.UserID, input.Type,
+	)
+	if err != nil {
+		return nil, a.dialect.TranslateError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, usecase.ErrNotFoundEntity
+	}
+
+	return &port.DeleteSanctionOutput{}, nil
+}
@@ -0,0 +1,95 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var (
+	_ port.UsersReader = (*UsersAccess)(nil)
+	_ port.UsersWriter = (*UsersAccess)(nil)
+)
+
+type UsersAccess struct {
+	db          *sql.DB
+	dialect     Dialect
+	idGenerator port.IDGenerator
+}
+
+func NewUsersAccess(db *sql.DB, dialect Dialect, idGenerator port.IDGenerator) *UsersAccess {
+	return &UsersAccess{
+		db:          db,
+		dialect:     dialect,
+		idGenerator: idGenerator,
+	}
+}
+
+func (a *UsersAccess) Get(ctx context.Context, input *port.GetUserInput) (*port.GetUserOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".UsersAccess.Get")
+	defer span.End()
+	var user entity.User
+	err := a.db.QueryRowContext(ctx, a.dialect.Rebind(`SELECT id, name FROM users WHERE id = ?`), input.ID).
+		Scan(&user.ID, &user.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &port.GetUserOutput{
+		User: &user,
+	}, nil
+}
+
+func (a *UsersAccess) GetCredential(ctx context.Context, input *port.GetUserCredentialInput) (*port.GetUserCredentialOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".UsersAccess.GetCredential")
+	defer span.End()
+	var (
+		user entity.User
+		hash []byte
+	)
+	err := a.db.QueryRowContext(ctx, a.dialect.Rebind(`SELECT id, name, password_hash FROM users WHERE name = ?`), input.Name).
+		Scan(&user.ID, &user.Name, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &port.GetUserCredentialOutput{
+		User:         &user,
+		PasswordHash: hash,
+	}, nil
+}
+
+func (a *UsersAccess) Create(ctx context.Context, input *port.CreateUserInput) (*port.CreateUserOutput, error) {
+	ctx, span := util.StartSpan(ctx, a.dialect.Name()+".UsersAccess.Create")
+	defer span.End()
+	id, err := a.idGenerator.Generate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate id: %w", err)
+	}
+	user := entity.User{
+		ID:   id,
+		Name: input.Name,
+	}
+	if _, err := a.db.ExecContext(ctx,
+		a.dialect.Rebind(`INSERT INTO users (id, name, password_hash) VALUES (?, ?, ?)`),
+		user.ID, user.Name, input.PasswordHash,
+	); err != nil {
+		return nil, a.dialect.TranslateError(err)
+	}
+
+	return &port.CreateUserOutput{
+		User: &user,
+	}, nil
+}
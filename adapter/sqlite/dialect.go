@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/adapter/sqldb"
+)
+
+// Dialect runs the stores of sqldb on sqlite.
+var Dialect sqldb.Dialect = dialect{}
+
+type dialect struct{}
+
+func (dialect) Name() string {
+	return "sqlite"
+}
+
+// Rebind keeps the query, sqlite takes ? placeholders.
+func (dialect) Rebind(query string) string {
+	return query
+}
+
+func (dialect) TimestampType() string {
+	return "TIMESTAMP"
+}
+
+// TimeValue stores t in unix microseconds.
+func (dialect) TimeValue(t time.Time) any {
+	return t.UnixMicro()
+}
+
+func (dialect) ScanTime(src any) (time.Time, error) {
+	micro, ok := src.(int64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected time value %T", src)
+	}
+	return time.UnixMicro(micro), nil
+}
+
+// MemberOrder follows the insertion order, room_members has no column of the time members joined.
+func (dialect) MemberOrder() string {
+	return "m.rowid"
+}
+
+func (dialect) TranslateError(err error) error {
+	return translateError(err)
+}
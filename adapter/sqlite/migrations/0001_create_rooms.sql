@@ -0,0 +1,5 @@
+CREATE TABLE rooms (
+    id          TEXT PRIMARY KEY,
+    name        TEXT NOT NULL,
+    description TEXT
+);
@@ -0,0 +1,4 @@
+ALTER TABLE messages ADD COLUMN idempotency_key TEXT;
+
+CREATE UNIQUE INDEX messages_idempotency_key ON messages (room_id, posted_by, idempotency_key)
+    WHERE idempotency_key IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE rooms ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,8 @@
+ALTER TABLE room_members ADD COLUMN role TEXT NOT NULL DEFAULT 'member';
+
+-- Rooms created before roles existed did not record their creator,
+-- the earliest member of each room becomes its owner.
+UPDATE room_members SET role = 'owner'
+WHERE rowid IN (
+    SELECT MIN(rowid) FROM room_members GROUP BY room_id
+);
@@ -0,0 +1,335 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	idAdapter "github.com/mkaiho/go-ws-sample/adapter/id"
+	"github.com/mkaiho/go-ws-sample/adapter/sqldb"
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(context.Background(), filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	// migrations already applied by Open must be skipped
+	require.NoError(t, Migrate(ctx, db))
+
+	list, err := sqldb.ListMigrations(migrations)
+	require.NoError(t, err)
+	var version int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version))
+	assert.Equal(t, list[len(list)-1].Version, version)
+}
+
+func TestRoomsAccess_Members(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	ids := idAdapter.NewULIDGenerator()
+	rooms := NewRoomsAccess(db, ids)
+	users := NewUsersAccess(db, ids)
+
+	owner, err := users.Create(ctx, &port.CreateUserInput{Name: "owner", PasswordHash: []byte("hash")})
+	require.NoError(t, err)
+	room, err := rooms.Create(ctx, &port.CreateRoomInput{Name: "room", Owner: owner.User})
+	require.NoError(t, err)
+	alice, err := users.Create(ctx, &port.CreateUserInput{Name: "alice", PasswordHash: []byte("hash")})
+	require.NoError(t, err)
+
+	_, err = rooms.AddMember(ctx, &port.AddRoomMemberInput{RoomID: room.Room.ID, User: alice.User, Role: entity.RoleModerator})
+	require.NoError(t, err)
+	_, err = rooms.AddMember(ctx, &port.AddRoomMemberInput{RoomID: room.Room.ID, User: alice.User, Role: entity.RoleMember})
+	assert.ErrorIs(t, err, usecase.ErrAlreadyExistsEntity)
+	_, err = rooms.AddMember(ctx, &port.AddRoomMemberInput{RoomID: "unknown", User: alice.User})
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+
+	got, err := rooms.Get(ctx, &port.GetRoomInput{ID: room.Room.ID})
+	require.NoError(t, err)
+	assert.Equal(t, entity.Members{
+		{User: owner.User, Role: entity.RoleOwner},
+		{User: alice.User, Role: entity.RoleModerator},
+	}, got.Room.Members)
+	member, err := rooms.GetMember(ctx, &port.GetRoomMemberInput{RoomID: room.Room.ID, UserID: alice.User.ID})
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoleModerator, member.Member.Role)
+
+	_, err = rooms.RemoveMember(ctx, &port.RemoveRoomMemberInput{RoomID: room.Room.ID, UserID: alice.User.ID})
+	require.NoError(t, err)
+	_, err = rooms.GetMember(ctx, &port.GetRoomMemberInput{RoomID: room.Room.ID, UserID: alice.User.ID})
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+
+	_, err = rooms.Delete(ctx, &port.DeleteRoomInput{ID: room.Room.ID})
+	require.NoError(t, err)
+	_, err = rooms.Delete(ctx, &port.DeleteRoomInput{ID: room.Room.ID})
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+}
+
+func TestSanctionsAccess(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	ids := idAdapter.NewULIDGenerator()
+	rooms := NewRoomsAccess(db, ids)
+	users := NewUsersAccess(db, ids)
+	sanctions := NewSanctionsAccess(db)
+
+	room, err := rooms.Create(ctx, &port.CreateRoomInput{Name: "room"})
+	require.NoError(t, err)
+	alice, err := users.Create(ctx, &port.CreateUserInput{Name: "alice", PasswordHash: []byte("hash")})
+	require.NoError(t, err)
+	ban := &port.GetSanctionInput{RoomID: room.Room.ID, UserID: alice.User.ID, Type: entity.SanctionTypeBan}
+	mute := &port.GetSanctionInput{RoomID: room.Room.ID, UserID: alice.User.ID, Type: entity.SanctionTypeMute}
+
+	_, err = sanctions.Get(ctx, ban)
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+	_, err = sanctions.Put(ctx, &port.PutSanctionInput{Sanction: &entity.Sanction{
+		RoomID: "unknown", UserID: alice.User.ID, Type: entity.SanctionTypeBan,
+	}})
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+
+	expiresAt := time.Now().Add(time.Hour)
+	put, err := sanctions.Put(ctx, &port.PutSanctionInput{Sanction: &entity.Sanction{
+		RoomID: room.Room.ID, UserID: alice.User.ID, Type: entity.SanctionTypeBan, ExpiresAt: &expiresAt,
+	}})
+	require.NoError(t, err)
+	got, err := sanctions.Get(ctx, ban)
+	require.NoError(t, err)
+	assert.True(t, put.Sanction.ExpiresAt.Equal(*got.Sanction.ExpiresAt))
+	_, err = sanctions.Get(ctx, mute)
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+
+	// putting again replaces the expiry, and an expired sanction is not found
+	expired := time.Now().Add(-time.Minute)
+	_, err = sanctions.Put(ctx, &port.PutSanctionInput{Sanction: &entity.Sanction{
+		RoomID: room.Room.ID, UserID: alice.User.ID, Type: entity.SanctionTypeBan, ExpiresAt: &expired,
+	}})
+	require.NoError(t, err)
+	_, err = sanctions.Get(ctx, ban)
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+
+	_, err = sanctions.Put(ctx, &port.PutSanctionInput{Sanction: &entity.Sanction{
+		RoomID: room.Room.ID, UserID: alice.User.ID, Type: entity.SanctionTypeMute,
+	}})
+	require.NoError(t, err)
+	got, err = sanctions.Get(ctx, mute)
+	require.NoError(t, err)
+	assert.Nil(t, got.Sanction.ExpiresAt)
+
+	_, err = sanctions.Delete(ctx, &port.DeleteSanctionInput{RoomID: room.Room.ID, UserID: alice.User.ID, Type: entity.SanctionTypeMute})
+	require.NoError(t, err)
+	_, err = sanctions.Delete(ctx, &port.DeleteSanctionInput{RoomID: room.Room.ID, UserID: alice.User.ID, Type: entity.SanctionTypeMute})
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+}
+
+func TestRoomsAccess_Find(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	rooms := NewRoomsAccess(db, idAdapter.NewULIDGenerator())
+
+	var created entity.Rooms
+	for _, name := range []string{"gamma", "alpha", "beta", "alphabet", "50%_off"} {
+		out, err := rooms.Create(ctx, &port.CreateRoomInput{Name: name})
+		require.NoError(t, err)
+		created = append(created, out.Room)
+	}
+	names := func(rooms entity.Rooms) []string {
+		var names []string
+		for _, room := range rooms {
+			names = append(names, room.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name  string
+		input *port.FindRoomsInput
+		want  []string
+	}{
+		{
+			name:  "order by created",
+			input: &port.FindRoomsInput{},
+			want:  []string{"gamma", "alpha", "beta", "alphabet", "50%_off"},
+		},
+		{
+			name:  "order by name",
+			input: &port.FindRoomsInput{Sort: port.RoomsSortKeyName},
+			want:  []string{"50%_off", "alpha", "alphabet", "beta", "gamma"},
+		},
+		{
+			name:  "filter by name ignoring case",
+			input: &port.FindRoomsInput{Query: "GA"},
+			want:  []string{"gamma"},
+		},
+		{
+			name:  "filter by wildcards of LIKE as they are",
+			input: &port.FindRoomsInput{Query: "%_"},
+			want:  []string{"50%_off"},
+		},
+		{
+			name: "return rooms after the position",
+			input: &port.FindRoomsInput{
+				Sort:  port.RoomsSortKeyName,
+				After: &port.RoomsPosition{ID: created[1].ID, Name: created[1].Name},
+				Limit: 1,
+			},
+			want: []string{"alphabet"},
+		},
+		{
+			name:  "return rooms after the created position",
+			input: &port.FindRoomsInput{Query: "a", After: &port.RoomsPosition{ID: created[1].ID}},
+			want:  []string{"beta", "alphabet"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rooms.Find(ctx, tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names(got.Rooms))
+		})
+	}
+}
+
+func TestRoomsAccess_Update(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	rooms := NewRoomsAccess(db, idAdapter.NewULIDGenerator())
+
+	description := "description"
+	room, err := rooms.Create(ctx, &port.CreateRoomInput{Name: "room", Description: &description})
+	require.NoError(t, err)
+
+	renamed := "renamed"
+	got, err := rooms.Update(ctx, &port.UpdateRoomInput{ID: room.Room.ID, Name: &renamed})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", got.Room.Name)
+	assert.Equal(t, &description, got.Room.Description)
+
+	var removed *string
+	got, err = rooms.Update(ctx, &port.UpdateRoomInput{ID: room.Room.ID, Description: &removed})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", got.Room.Name)
+	assert.Nil(t, got.Room.Description)
+
+	_, err = rooms.Update(ctx, &port.UpdateRoomInput{ID: "unknown", Name: &renamed})
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+}
+
+func TestRoomsAccess_Version(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	rooms := NewRoomsAccess(db, idAdapter.NewULIDGenerator())
+
+	room, err := rooms.Create(ctx, &port.CreateRoomInput{Name: "room"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, room.Room.Version)
+
+	renamed := "renamed"
+	got, err := rooms.Update(ctx, &port.UpdateRoomInput{ID: room.Room.ID, Name: &renamed, Version: &room.Room.Version})
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Room.Version)
+
+	// the version read before the update is stale
+	_, err = rooms.Update(ctx, &port.UpdateRoomInput{ID: room.Room.ID, Name: &renamed, Version: &room.Room.Version})
+	assert.ErrorIs(t, err, usecase.ErrVersionConflict)
+	_, err = rooms.Delete(ctx, &port.DeleteRoomInput{ID: room.Room.ID, Version: &room.Room.Version})
+	assert.ErrorIs(t, err, usecase.ErrVersionConflict)
+
+	_, err = rooms.Delete(ctx, &port.DeleteRoomInput{ID: room.Room.ID, Version: &got.Room.Version})
+	require.NoError(t, err)
+	_, err = rooms.Delete(ctx, &port.DeleteRoomInput{ID: room.Room.ID, Version: &got.Room.Version})
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+}
+
+func TestMessagesAccess_Find(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	ids := idAdapter.NewULIDGenerator()
+	rooms := NewRoomsAccess(db, ids)
+	users := NewUsersAccess(db, ids)
+	messages := NewMessagesAccess(db, ids)
+
+	room, err := rooms.Create(ctx, &port.CreateRoomInput{Name: "room"})
+	require.NoError(t, err)
+	alice, err := users.Create(ctx, &port.CreateUserInput{Name: "alice", PasswordHash: []byte("hash")})
+	require.NoError(t, err)
+	var posted []*entity.PostMessage
+	for _, body := range []string{"first", "second", "third"} {
+		out, err := messages.Create(ctx, &port.CreateMessageInput{RoomID: room.Room.ID, Body: body, PostedBy: alice.User, IdempotencyKey: body})
+		require.NoError(t, err)
+		require.True(t, out.Created)
+		posted = append(posted, out.Message)
+	}
+	retried, err := messages.Create(ctx, &port.CreateMessageInput{RoomID: room.Room.ID, Body: "first", PostedBy: alice.User, IdempotencyKey: "first"})
+	require.NoError(t, err)
+	assert.False(t, retried.Created)
+	assert.Equal(t, posted[0].ID, retried.Message.ID)
+
+	tests := []struct {
+		name  string
+		input *port.FindMessagesInput
+		want  []string
+	}{
+		{
+			name:  "return all messages newest first",
+			input: &port.FindMessagesInput{RoomID: room.Room.ID},
+			want:  []string{"third", "second", "first"},
+		},
+		{
+			name:  "return limited messages",
+			input: &port.FindMessagesInput{RoomID: room.Room.ID, Limit: 2},
+			want:  []string{"third", "second"},
+		},
+		{
+			name:  "return messages before cursor",
+			input: &port.FindMessagesInput{RoomID: room.Room.ID, Before: &posted[2].ID, Limit: 2},
+			want:  []string{"second", "first"},
+		},
+		{
+			name:  "return messages after cursor oldest first",
+			input: &port.FindMessagesInput{RoomID: room.Room.ID, After: &posted[0].ID},
+			want:  []string{"second", "third"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := messages.Find(ctx, tt.input)
+			require.NoError(t, err)
+			var bodies []string
+			for _, m := range got.Messages {
+				bodies = append(bodies, m.Body)
+				assert.Equal(t, alice.User, m.PostedBy)
+			}
+			assert.Equal(t, tt.want, bodies)
+		})
+	}
+
+	_, err = messages.Delete(ctx, &port.DeleteMessagesInput{RoomID: room.Room.ID, MessageID: &posted[1].ID})
+	require.NoError(t, err)
+	_, err = messages.Delete(ctx, &port.DeleteMessagesInput{RoomID: room.Room.ID, MessageID: &posted[1].ID})
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+	got, err := messages.Find(ctx, &port.FindMessagesInput{RoomID: room.Room.ID})
+	require.NoError(t, err)
+	assert.Len(t, got.Messages, 2)
+
+	// the messages are deleted along with their room
+	_, err = rooms.Delete(ctx, &port.DeleteRoomInput{ID: room.Room.ID})
+	require.NoError(t, err)
+	got, err = messages.Find(ctx, &port.FindMessagesInput{RoomID: room.Room.ID})
+	require.NoError(t, err)
+	assert.Empty(t, got.Messages)
+}
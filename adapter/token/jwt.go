@@ -0,0 +1,84 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ (port.TokenManager) = (*JWTManager)(nil)
+
+const issuer = "go-ws-sample"
+
+// JWTManager issues HMAC-SHA256 signed JWTs whose subject is the user ID.
+type JWTManager struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewJWTManager(secret []byte, ttl time.Duration) *JWTManager {
+	return &JWTManager{
+		secret: secret,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+func (m *JWTManager) Issue(ctx context.Context, input *port.IssueTokenInput) (*port.IssueTokenOutput, error) {
+	_, span := util.StartSpan(ctx, "token.JWTManager.Issue")
+	defer span.End()
+	now := m.now()
+	expiresAt := now.Add(m.ttl)
+	claims := jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Subject:   input.User.ID.String(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return &port.IssueTokenOutput{
+		Token:     signed,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (m *JWTManager) Verify(ctx context.Context, input *port.VerifyTokenInput) (*port.VerifyTokenOutput, error) {
+	_, span := util.StartSpan(ctx, "token.JWTManager.Verify")
+	defer span.End()
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(input.Token, &claims, func(t *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(m.now),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, fmt.Errorf("%w: token is expired", usecase.ErrInvalidCredential)
+		}
+		return nil, fmt.Errorf("%w: %s", usecase.ErrInvalidCredential, err.Error())
+	}
+	userID, err := entity.ParseID(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", usecase.ErrInvalidCredential, err.Error())
+	}
+
+	return &port.VerifyTokenOutput{
+		UserID: userID,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package token
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTManager_Verify(t *testing.T) {
+	user := &entity.User{ID: "01HNZ0000000000000000000AA", Name: "alice"}
+	issuedAt := time.Date(2024, 2, 8, 0, 0, 0, 0, time.UTC)
+	type args struct {
+		secret []byte
+		now    time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *port.VerifyTokenOutput
+		wantErr error
+	}{
+		{
+			name: "return user id of valid token",
+			args: args{
+				secret: []byte("secret"),
+				now:    issuedAt.Add(time.Minute),
+			},
+			want: &port.VerifyTokenOutput{
+				UserID: user.ID,
+			},
+		},
+		{
+			name: "return ErrInvalidCredential when token is expired",
+			args: args{
+				secret: []byte("secret"),
+				now:    issuedAt.Add(time.Hour),
+			},
+			wantErr: usecase.ErrInvalidCredential,
+		},
+		{
+			name: "return ErrInvalidCredential when signature does not match",
+			args: args{
+				secret: []byte("another secret"),
+				now:    issuedAt.Add(time.Minute),
+			},
+			wantErr: usecase.ErrInvalidCredential,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			issuer := NewJWTManager([]byte("secret"), 15*time.Minute)
+			issuer.now = func() time.Time { return issuedAt }
+			issued, err := issuer.Issue(ctx, &port.IssueTokenInput{User: user})
+			require.NoError(t, err)
+
+			verifier := NewJWTManager(tt.args.secret, 15*time.Minute)
+			verifier.now = func() time.Time { return tt.args.now }
+			got, err := verifier.Verify(ctx, &port.VerifyTokenInput{Token: issued.Token})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
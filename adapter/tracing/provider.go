@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const ServiceName = "go-ws-sample"
+
+type Exporter string
+
+const (
+	ExporterStdout Exporter = "stdout"
+	ExporterOTLP   Exporter = "otlp"
+	ExporterNone   Exporter = "none"
+)
+
+func ParseExporterStr(v string) (Exporter, error) {
+	switch e := Exporter(v); e {
+	case ExporterStdout, ExporterOTLP, ExporterNone:
+		return e, nil
+	default:
+		return "", fmt.Errorf("unknown trace exporter: %s", v)
+	}
+}
+
+// NewTracerProvider returns a provider exporting spans with the exporter.
+// endpoint is the host:port of the OTLP/HTTP collector, the OTEL_EXPORTER_OTLP_* variables apply if it is empty.
+func NewTracerProvider(ctx context.Context, exporter Exporter, endpoint string) (*sdktrace.TracerProvider, error) {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(ServiceName))),
+	}
+	switch exporter {
+	case ExporterStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case ExporterOTLP:
+		var expOpts []otlptracehttp.Option
+		if len(endpoint) > 0 {
+			expOpts = append(expOpts, otlptracehttp.WithEndpoint(endpoint), otlptracehttp.WithInsecure())
+		}
+		exp, err := otlptracehttp.New(ctx, expOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case ExporterNone:
+	default:
+		return nil, fmt.Errorf("unknown trace exporter: %s", exporter)
+	}
+	return sdktrace.NewTracerProvider(opts...), nil
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		wantErr  bool
+	}{
+		{
+			name:     "create provider exporting to stdout",
+			exporter: "stdout",
+		},
+		{
+			name:     "create provider exporting to otlp collector",
+			exporter: "otlp",
+		},
+		{
+			name:     "create provider without exporter",
+			exporter: "none",
+		},
+		{
+			name:     "return error when exporter is unknown",
+			exporter: "jaeger",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			exporter, err := ParseExporterStr(tt.exporter)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			tp, err := NewTracerProvider(ctx, exporter, "localhost:4318")
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, span := tp.Tracer("test").Start(ctx, "span")
+			assert.True(t, span.SpanContext().IsValid())
+			span.End()
+			// nothing is listening on the collector endpoint, so only the provider is shut down
+			tp.Shutdown(context.Background())
+		})
+	}
+}
@@ -2,13 +2,19 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
 	"os"
 	"time"
 
 	"github.com/gorilla/websocket"
+	idAdapter "github.com/mkaiho/go-ws-sample/adapter/id"
+	"github.com/mkaiho/go-ws-sample/controller/ws/protocol"
 	"github.com/mkaiho/go-ws-sample/util"
 	"github.com/spf13/cobra"
 )
@@ -60,45 +66,126 @@ func newCommand() *cobra.Command {
 		SilenceErrors: true,
 	}
 	command.Flags().IntP("port", "", 3000, "listening port")
-	command.Flags().StringP("host", "", "", "host name")
+	command.Flags().StringP("host", "", "localhost", "host name")
+	command.Flags().StringP("room", "", "", "room id")
+	command.Flags().StringP("user", "", "", "user for basic authentication")
+	command.Flags().StringP("password", "", "", "password for basic authentication")
+	command.Flags().StringP("token", "", "", "access token used instead of basic authentication")
+	command.Flags().StringP("since", "", "", "id of the last received message to replay the messages after it")
+	command.Flags().BoolP("tls", "", false, "connect with wss")
+	command.Flags().StringP("ca-cert", "", "", "PEM CA bundle verifying the server certificate (system roots if empty)")
+	command.Flags().StringP("cert", "", "", "PEM client certificate file presented to servers requiring one")
+	command.Flags().StringP("key", "", "", "PEM private key file of --cert")
+	_ = command.MarkFlagRequired("room")
+	command.MarkFlagsRequiredTogether("cert", "key")
 
 	return &command
 }
 
+type options struct {
+	host     string
+	port     int
+	room     string
+	user     string
+	password string
+	token    string
+	since    string
+	tls      bool
+	caCert   string
+	cert     string
+	key      string
+}
+
 func handle(cmd *cobra.Command, args []string) (err error) {
-	var (
-		host string
-		port int
-	)
+	var opts options
 	ctx := util.NewContextWithLogger(context.Background(), util.GLogger())
 	logger := util.FromContext(ctx)
 	if initErr != nil {
 		return initErr
 	}
 
-	host, err = cmd.Flags().GetString("host")
+	opts.host, err = cmd.Flags().GetString("host")
+	if err != nil {
+		return err
+	}
+	opts.port, err = cmd.Flags().GetInt("port")
+	if err != nil {
+		return err
+	}
+	opts.room, err = cmd.Flags().GetString("room")
+	if err != nil {
+		return err
+	}
+	opts.user, err = cmd.Flags().GetString("user")
+	if err != nil {
+		return err
+	}
+	opts.password, err = cmd.Flags().GetString("password")
+	if err != nil {
+		return err
+	}
+	opts.token, err = cmd.Flags().GetString("token")
+	if err != nil {
+		return err
+	}
+	opts.since, err = cmd.Flags().GetString("since")
+	if err != nil {
+		return err
+	}
+	opts.tls, err = cmd.Flags().GetBool("tls")
+	if err != nil {
+		return err
+	}
+	opts.caCert, err = cmd.Flags().GetString("ca-cert")
 	if err != nil {
 		return err
 	}
-	port, err = cmd.Flags().GetInt("port")
+	opts.cert, err = cmd.Flags().GetString("cert")
+	if err != nil {
+		return err
+	}
+	opts.key, err = cmd.Flags().GetString("key")
 	if err != nil {
 		return err
 	}
 
 	logger.
-		WithValues("host", host).
-		WithValues("port", port).
-		Info("launch server")
-	return exec(ctx)
+		WithValues("host", opts.host).
+		WithValues("port", opts.port).
+		WithValues("room", opts.room).
+		WithValues("user", opts.user).
+		WithValues("tls", opts.tls).
+		Info("launch client")
+	return exec(ctx, &opts)
 }
 
-func exec(ctx context.Context) error {
+func exec(ctx context.Context, opts *options) error {
 	logger := util.FromContext(ctx)
 	// WebSocketサーバのURL
-	u := url.URL{Scheme: "ws", Host: "localhost:3000", Path: "/rooms/1234/messages"}
+	scheme := "ws"
+	if opts.tls {
+		scheme = "wss"
+	}
+	u := url.URL{Scheme: scheme, Host: fmt.Sprintf("%s:%d", opts.host, opts.port), Path: fmt.Sprintf("/rooms/%s/messages", opts.room)}
+	if len(opts.since) > 0 {
+		u.RawQuery = url.Values{"since": {opts.since}}.Encode()
+	}
+
+	// 認証ヘッダ
+	header := http.Header{}
+	if len(opts.token) > 0 {
+		header.Set("Authorization", "Bearer "+opts.token)
+	} else {
+		credential := base64.StdEncoding.EncodeToString([]byte(opts.user + ":" + opts.password))
+		header.Set("Authorization", "Basic "+credential)
+	}
 
 	// WebSocketサーバに接続
-	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	dialer, err := newDialer(opts)
+	if err != nil {
+		return err
+	}
+	c, _, err := dialer.Dial(u.String(), header)
 	if err != nil {
 		log.Fatal("dial:", err)
 	}
@@ -115,12 +202,28 @@ func exec(ctx context.Context) error {
 				logger.Error(err, "failed to read message")
 				return
 			}
-			logger.Info("recieved", "message", string(message))
+			envelope := protocol.Envelope{}
+			if err := json.Unmarshal(message, &envelope); err != nil {
+				logger.Error(err, "failed to decode message", "message", string(message))
+				continue
+			}
+			logger.Info("recieved", "type", envelope.Type, "id", envelope.ID, "payload", string(envelope.Payload))
 		}
 	}()
 
 	// サーバにメッセージを送信
-	err = c.WriteMessage(websocket.TextMessage, []byte("hello"))
+	key, err := idAdapter.NewULIDGenerator().Generate(ctx)
+	if err != nil {
+		return err
+	}
+	frame, err := protocol.Encode(protocol.TypeMessagePost, "1", "", &protocol.MessagePostPayload{
+		Body:           "hello",
+		IdempotencyKey: key.String(),
+	})
+	if err != nil {
+		return err
+	}
+	err = c.WriteMessage(websocket.TextMessage, frame)
 	if err != nil {
 		return err
 	}
@@ -140,3 +243,29 @@ func exec(ctx context.Context) error {
 
 	return nil
 }
+
+func newDialer(opts *options) (*websocket.Dialer, error) {
+	dialer := *websocket.DefaultDialer
+	if !opts.tls {
+		return &dialer, nil
+	}
+	tlsConf := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if len(opts.caCert) > 0 {
+		pool, err := util.LoadCertPool(opts.caCert)
+		if err != nil {
+			return nil, err
+		}
+		tlsConf.RootCAs = pool
+	}
+	if len(opts.cert) > 0 {
+		cert, err := tls.LoadX509KeyPair(opts.cert, opts.key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+	dialer.TLSClientConfig = tlsConf
+	return &dialer, nil
+}
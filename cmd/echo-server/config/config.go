@@ -0,0 +1,362 @@
+// Package config loads the settings of echo-server.
+// Each setting is read from a YAML file, then from an environment variable and then from a flag,
+// the later sources override the earlier ones.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	tracingAdapter "github.com/mkaiho/go-ws-sample/adapter/tracing"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+const (
+	// EnvPrefix prefixes the environment variables, ECHO_SERVER_TOKEN_SECRET sets --token-secret.
+	EnvPrefix = "ECHO_SERVER_"
+	// FileFlag is the flag naming the YAML file, ECHO_SERVER_CONFIG also names it.
+	FileFlag = "config"
+
+	maskedSecret = "*****"
+)
+
+type Config struct {
+	Host         string          `yaml:"host" json:"host"`
+	Port         int             `yaml:"port" json:"port"`
+	TLS          TLSConfig       `yaml:"tls" json:"tls"`
+	Log          LogConfig       `yaml:"log" json:"log"`
+	DB           string          `yaml:"db" json:"db"`
+	RedisURL     string          `yaml:"redis_url" json:"redisURL"`
+	DrainTimeout time.Duration   `yaml:"drain_timeout" json:"drainTimeout"`
+	Token        TokenConfig     `yaml:"token" json:"token"`
+	WebSocket    WSConfig        `yaml:"websocket" json:"websocket"`
+	Trace        TraceConfig     `yaml:"trace" json:"trace"`
+	RateLimit    RateLimitConfig `yaml:"rate_limit" json:"rateLimit"`
+}
+
+// TLSConfig enables HTTPS when Cert and Key are set.
+type TLSConfig struct {
+	Cert string `yaml:"cert" json:"cert"`
+	Key  string `yaml:"key" json:"key"`
+	// ClientCA is a CA bundle verifying the certificates clients must present.
+	ClientCA       string        `yaml:"client_ca" json:"clientCA"`
+	ReloadInterval time.Duration `yaml:"reload_interval" json:"reloadInterval"`
+}
+
+func (c TLSConfig) Enabled() bool {
+	return len(c.Cert) > 0
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level" json:"level"`
+	Format string `yaml:"format" json:"format"`
+}
+
+type TokenConfig struct {
+	// Secret signs the access tokens, a random one is used if it is empty.
+	Secret string        `yaml:"secret" json:"secret"`
+	TTL    time.Duration `yaml:"ttl" json:"ttl"`
+}
+
+type WSConfig struct {
+	ReadBufferSize  int `yaml:"read_buffer_size" json:"readBufferSize"`
+	WriteBufferSize int `yaml:"write_buffer_size" json:"writeBufferSize"`
+	// QueueSize is the number of events buffered for each connection.
+	QueueSize int `yaml:"queue_size" json:"queueSize"`
+}
+
+type RateLimitConfig struct {
+	// Routes limits the requests of each caller to the routes, keyed by method and path template such as "POST /rooms".
+	Routes map[string]RateConfig `yaml:"routes" json:"routes"`
+	// Credentials limits the requests carrying a password of each client IP across all the routes, before verifying it.
+	Credentials RateConfig `yaml:"credentials" json:"credentials"`
+	// Frames limits the frames each WebSocket connection sends.
+	Frames RateConfig `yaml:"frames" json:"frames"`
+}
+
+// RateConfig allows Rate events per second with bursts of Burst events, a zero rate disables the limit.
+type RateConfig struct {
+	Rate  float64 `yaml:"rate" json:"rate"`
+	Burst int     `yaml:"burst" json:"burst"`
+}
+
+func (c RateConfig) Enabled() bool {
+	return c.Rate > 0
+}
+
+type TraceConfig struct {
+	Exporter     string `yaml:"exporter" json:"exporter"`
+	OTLPEndpoint string `yaml:"otlp_endpoint" json:"otlpEndpoint"`
+}
+
+func Default() *Config {
+	return &Config{
+		Port: 3000,
+		TLS: TLSConfig{
+			ReloadInterval: 10 * time.Second,
+		},
+		Log: LogConfig{
+			Level:  util.LoggerLevelDebug.String(),
+			Format: util.LoggerFormatJSON.String(),
+		},
+		DrainTimeout: 10 * time.Second,
+		Token: TokenConfig{
+			TTL: 15 * time.Minute,
+		},
+		WebSocket: WSConfig{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			QueueSize:       64,
+		},
+		Trace: TraceConfig{
+			Exporter: string(tracingAdapter.ExporterStdout),
+		},
+		RateLimit: RateLimitConfig{
+			Routes: map[string]RateConfig{
+				"POST /users":      {Rate: 1, Burst: 5},
+				"POST /auth/token": {Rate: 1, Burst: 5},
+				"POST /rooms":      {Rate: 1, Burst: 10},
+			},
+			Credentials: RateConfig{Rate: 1, Burst: 5},
+			Frames:      RateConfig{Rate: 10, Burst: 20},
+		},
+	}
+}
+
+// setting binds a flag, and the environment variable named after it, to a field of Config.
+type setting struct {
+	flag  string
+	usage string
+	// value points to a string, int, float64 or time.Duration field
+	value any
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{flag: "host", usage: "host name", value: &c.Host},
+		{flag: "port", usage: "listening port", value: &c.Port},
+		{flag: "tls-cert", usage: "PEM certificate file to serve HTTPS and WSS (plain HTTP if empty)", value: &c.TLS.Cert},
+		{flag: "tls-key", usage: "PEM private key file of --tls-cert", value: &c.TLS.Key},
+		{flag: "tls-client-ca", usage: "PEM CA bundle to require and verify client certificates", value: &c.TLS.ClientCA},
+		{flag: "tls-reload-interval", usage: "interval to check the certificate files for changes", value: &c.TLS.ReloadInterval},
+		{flag: "log-level", usage: "log level (debug, info, warn or error)", value: &c.Log.Level},
+		{flag: "log-format", usage: "log format (json or console)", value: &c.Log.Format},
+		{flag: "db", usage: "postgres:// url or sqlite database file (in-memory dummy store if empty)", value: &c.DB},
+		{flag: "redis-url", usage: "redis:// url to fan out messages across instances (in-process if empty)", value: &c.RedisURL},
+		{flag: "drain-timeout", usage: "time to wait for connections to drain on shutdown", value: &c.DrainTimeout},
+		{flag: "token-secret", usage: "secret to sign access tokens (random if empty)", value: &c.Token.Secret},
+		{flag: "token-ttl", usage: "lifetime of access tokens", value: &c.Token.TTL},
+		{flag: "ws-read-buffer-size", usage: "size in bytes of the WebSocket read buffer", value: &c.WebSocket.ReadBufferSize},
+		{flag: "ws-write-buffer-size", usage: "size in bytes of the WebSocket write buffer", value: &c.WebSocket.WriteBufferSize},
+		{flag: "ws-queue-size", usage: "number of events buffered for each WebSocket connection", value: &c.WebSocket.QueueSize},
+		{flag: "credential-rate", usage: "requests carrying a password per second each client IP may send (unlimited if 0)", value: &c.RateLimit.Credentials.Rate},
+		{flag: "credential-burst", usage: "requests carrying a password each client IP may send in a burst", value: &c.RateLimit.Credentials.Burst},
+		{flag: "ws-frame-rate", usage: "frames per second each WebSocket connection may send (unlimited if 0)", value: &c.RateLimit.Frames.Rate},
+		{flag: "ws-frame-burst", usage: "frames each WebSocket connection may send in a burst", value: &c.RateLimit.Frames.Burst},
+		{flag: "trace-exporter", usage: "trace exporter (stdout, otlp or none)", value: &c.Trace.Exporter},
+		{flag: "otlp-endpoint", usage: "host:port of the OTLP/HTTP collector (OTEL_EXPORTER_OTLP_ENDPOINT if empty)", value: &c.Trace.OTLPEndpoint},
+	}
+}
+
+// RegisterFlags adds the flags of the settings to fs, defaulting to the values of Default.
+func RegisterFlags(fs *pflag.FlagSet) {
+	fs.StringP(FileFlag, "", "", "YAML config file ("+envName(FileFlag)+" if empty)")
+	for _, s := range Default().settings() {
+		switch v := s.value.(type) {
+		case *string:
+			fs.StringP(s.flag, "", *v, s.usage)
+		case *int:
+			fs.IntP(s.flag, "", *v, s.usage)
+		case *float64:
+			fs.Float64P(s.flag, "", *v, s.usage)
+		case *time.Duration:
+			fs.DurationP(s.flag, "", *v, s.usage)
+		}
+	}
+}
+
+// Load reads the config file, the environment variables and the flags set on the command line over Default.
+func Load(fs *pflag.FlagSet, lookupEnv func(key string) (string, bool)) (*Config, error) {
+	conf := Default()
+
+	path, err := fs.GetString(FileFlag)
+	if err != nil {
+		return nil, err
+	}
+	if !fs.Changed(FileFlag) {
+		path, _ = lookupEnv(envName(FileFlag))
+	}
+	if len(path) > 0 {
+		if err := conf.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range conf.settings() {
+		if v, ok := lookupEnv(envName(s.flag)); ok {
+			if err := set(s.value, v); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", envName(s.flag), err)
+			}
+		}
+		if fs.Changed(s.flag) {
+			if err := set(s.value, fs.Lookup(s.flag).Value.String()); err != nil {
+				return nil, fmt.Errorf("invalid --%s: %w", s.flag, err)
+			}
+		}
+	}
+
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	// misspelled keys would be ignored silently otherwise
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port must be between 1 and 65535: %d", c.Port))
+	}
+	if len(c.TLS.Cert) > 0 != (len(c.TLS.Key) > 0) {
+		errs = append(errs, errors.New("tls cert and key must be set together"))
+	}
+	if len(c.TLS.ClientCA) > 0 && !c.TLS.Enabled() {
+		errs = append(errs, errors.New("tls client ca requires tls cert and key"))
+	}
+	if c.TLS.ReloadInterval <= 0 {
+		errs = append(errs, fmt.Errorf("tls reload interval must be positive: %s", c.TLS.ReloadInterval))
+	}
+	if util.ParseLoggerLevelStr(c.Log.Level).String() != c.Log.Level {
+		errs = append(errs, fmt.Errorf("unknown log level: %s", c.Log.Level))
+	}
+	if util.ParseLoggerFormatStr(c.Log.Format).String() != c.Log.Format {
+		errs = append(errs, fmt.Errorf("unknown log format: %s", c.Log.Format))
+	}
+	if c.DrainTimeout < 0 {
+		errs = append(errs, fmt.Errorf("drain timeout must not be negative: %s", c.DrainTimeout))
+	}
+	if c.Token.TTL <= 0 {
+		errs = append(errs, fmt.Errorf("token ttl must be positive: %s", c.Token.TTL))
+	}
+	if c.WebSocket.ReadBufferSize <= 0 {
+		errs = append(errs, fmt.Errorf("websocket read buffer size must be positive: %d", c.WebSocket.ReadBufferSize))
+	}
+	if c.WebSocket.WriteBufferSize <= 0 {
+		errs = append(errs, fmt.Errorf("websocket write buffer size must be positive: %d", c.WebSocket.WriteBufferSize))
+	}
+	if c.WebSocket.QueueSize <= 0 {
+		errs = append(errs, fmt.Errorf("websocket queue size must be positive: %d", c.WebSocket.QueueSize))
+	}
+	if _, err := tracingAdapter.ParseExporterStr(c.Trace.Exporter); err != nil {
+		errs = append(errs, err)
+	}
+	for route, rate := range c.RateLimit.Routes {
+		if _, _, err := ParseRoute(route); err != nil {
+			errs = append(errs, err)
+		}
+		if err := rate.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("rate limit of %s %w", route, err))
+		}
+	}
+	if err := c.RateLimit.Credentials.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("credential rate limit %w", err))
+	}
+	if err := c.RateLimit.Frames.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("websocket frame rate limit %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (c RateConfig) validate() error {
+	if c.Rate < 0 {
+		return fmt.Errorf("must not have negative rate: %v", c.Rate)
+	}
+	if c.Enabled() && c.Burst < 1 {
+		return fmt.Errorf("must have positive burst: %d", c.Burst)
+	}
+	return nil
+}
+
+// ParseRoute splits a route key such as "POST /rooms" into the method and the path template.
+func ParseRoute(route string) (method string, path string, err error) {
+	method, path, ok := strings.Cut(route, " ")
+	if !ok || len(method) == 0 || method != strings.ToUpper(method) || !strings.HasPrefix(path, "/") {
+		return "", "", fmt.Errorf("route must be a method and a path such as \"POST /rooms\": %q", route)
+	}
+	return method, path, nil
+}
+
+// Masked returns a copy of the config safe to log, the secrets and the credentials of urls are masked.
+func (c *Config) Masked() *Config {
+	masked := *c
+	if len(masked.Token.Secret) > 0 {
+		masked.Token.Secret = maskedSecret
+	}
+	masked.DB = maskURL(masked.DB)
+	masked.RedisURL = maskURL(masked.RedisURL)
+	return &masked
+}
+
+func maskURL(v string) string {
+	u, err := url.Parse(v)
+	if err != nil {
+		return v
+	}
+	return u.Redacted()
+}
+
+func set(field any, v string) error {
+	switch f := field.(type) {
+	case *string:
+		*f = v
+	case *int:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*f = n
+	case *float64:
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		*f = n
+	case *time.Duration:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*f = d
+	default:
+		return fmt.Errorf("unsupported setting type %T", field)
+	}
+	return nil
+}
+
+func envName(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
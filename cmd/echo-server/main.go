@@ -2,18 +2,40 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/tls"
 	"fmt"
+	"net"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 
+	busAdapter "github.com/mkaiho/go-ws-sample/adapter/bus"
 	"github.com/mkaiho/go-ws-sample/adapter/dummy"
+	hubAdapter "github.com/mkaiho/go-ws-sample/adapter/hub"
 	idAdapter "github.com/mkaiho/go-ws-sample/adapter/id"
+	metricsAdapter "github.com/mkaiho/go-ws-sample/adapter/metrics"
+	passwordAdapter "github.com/mkaiho/go-ws-sample/adapter/password"
+	postgresAdapter "github.com/mkaiho/go-ws-sample/adapter/postgres"
+	ratelimitAdapter "github.com/mkaiho/go-ws-sample/adapter/ratelimit"
+	sqliteAdapter "github.com/mkaiho/go-ws-sample/adapter/sqlite"
+	tokenAdapter "github.com/mkaiho/go-ws-sample/adapter/token"
+	tracingAdapter "github.com/mkaiho/go-ws-sample/adapter/tracing"
+	"github.com/mkaiho/go-ws-sample/cmd/echo-server/config"
 	"github.com/mkaiho/go-ws-sample/controller/web"
 	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+	"github.com/mkaiho/go-ws-sample/controller/web/middlewares"
 	"github.com/mkaiho/go-ws-sample/controller/web/routes"
+	"github.com/mkaiho/go-ws-sample/entity"
 	"github.com/mkaiho/go-ws-sample/usecase/interactor"
 	"github.com/mkaiho/go-ws-sample/usecase/port"
 	"github.com/mkaiho/go-ws-sample/util"
+	"github.com/redis/go-redis/v9"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 var (
@@ -22,17 +44,14 @@ var (
 )
 
 func init() {
-	util.InitGLogger(
-		util.OptionLoggerLevel(util.LoggerLevelDebug),
-		util.OptionLoggerFormat(util.LoggerFormatJSON),
-	)
 	command = newCommand()
 }
 
 func main() {
 	var err error
-	logger := util.GLogger()
 	defer func() {
+		// the logger is configured while the command runs
+		logger := util.GLogger()
 		if p := recover(); p != nil {
 			msg := "panic has occured"
 			if pErr, ok := p.(error); ok {
@@ -57,58 +76,171 @@ func newCommand() *cobra.Command {
 	command := cobra.Command{
 		Use:           "echo-server args...",
 		Short:         "launch echo-server",
-		Long:          "launch echo-server.",
+		Long:          "launch echo-server.\n\nSettings are read from the --config YAML file, then from " + config.EnvPrefix + "* environment variables and then from flags.",
 		RunE:          handle,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
-	command.Flags().IntP("port", "", 3000, "listening port")
-	command.Flags().StringP("host", "", "", "host name")
+	config.RegisterFlags(command.Flags())
 
 	return &command
 }
 
 func handle(cmd *cobra.Command, args []string) (err error) {
-	var (
-		host string
-		port int
-	)
-	ctx := util.NewContextWithLogger(context.Background(), util.GLogger())
-	logger := util.FromContext(ctx)
 	if initErr != nil {
 		return initErr
 	}
-
-	host, err = cmd.Flags().GetString("host")
+	conf, err := config.Load(cmd.Flags(), os.LookupEnv)
 	if err != nil {
-		return err
+		return fmt.Errorf("invalid config: %w", err)
 	}
-	port, err = cmd.Flags().GetInt("port")
+	util.InitGLogger(
+		util.OptionLoggerLevel(util.ParseLoggerLevelStr(conf.Log.Level)),
+		util.OptionLoggerFormat(util.ParseLoggerFormatStr(conf.Log.Format)),
+	)
+	ctx := util.NewContextWithLogger(context.Background(), util.GLogger())
+	logger := util.FromContext(ctx)
+	logger.WithValues("config", conf.Masked()).Info("loaded config")
+
+	server, err := server(ctx, conf)
 	if err != nil {
 		return err
 	}
 
-	server, err := server()
-	if err != nil {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	logger.
+		WithValues("host", conf.Host).
+		WithValues("port", conf.Port).
+		WithValues("tls", conf.TLS.Enabled()).
+		Info("launch server")
+	addr := net.JoinHostPort(conf.Host, strconv.Itoa(conf.Port))
+	runErr := make(chan error, 1)
+	if conf.TLS.Enabled() {
+		tlsConf, err := tlsConfig(ctx, server, &conf.TLS)
+		if err != nil {
+			return err
+		}
+		go func() {
+			runErr <- server.RunTLS(addr, tlsConf)
+		}()
+	} else {
+		go func() {
+			runErr <- server.Run(addr)
+		}()
+	}
+	select {
+	case err := <-runErr:
 		return err
+	case <-ctx.Done():
 	}
+	// a second signal terminates immediately
+	stop()
 
 	logger.
-		WithValues("host", host).
-		WithValues("port", port).
-		Info("launch server")
-	return server.Run(fmt.Sprintf("%s:%d", "", port))
+		WithValues("drainTimeout", conf.DrainTimeout).
+		Info("shut down server")
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), conf.DrainTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+	return <-runErr
 }
 
-func server() (*web.Server, error) {
+func server(ctx context.Context, conf *config.Config) (*web.Server, error) {
+	logger := util.FromContext(ctx)
+	tokenSecret := []byte(conf.Token.Secret)
+	if len(tokenSecret) == 0 {
+		tokenSecret = make([]byte, 32)
+		if _, err := rand.Read(tokenSecret); err != nil {
+			return nil, fmt.Errorf("failed to generate token secret: %w", err)
+		}
+		logger.Warn(nil, "token secret is not specified, tokens are invalidated on restart")
+	}
+
+	tracerProvider, err := tracingAdapter.NewTracerProvider(ctx, tracingAdapter.Exporter(conf.Trace.Exporter), conf.Trace.OTLPEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
 	// ports
 	var (
-		ulidGenerator port.IDGenerator
-		roomsManager  port.RoomsManager
+		ulidGenerator    port.IDGenerator
+		roomsManager     port.RoomsManager
+		messagesManager  port.MessagesManager
+		sanctionsManager port.SanctionsManager
+		messageBus       port.MessageBus
+		roomHub          port.RoomHub
+		usersManager     port.UsersManager
+		passwordHasher   port.PasswordHasher
+		tokenManager     port.TokenManager
+		metrics          port.Metrics
+		rateLimiter      port.RateLimiter
+
+		roomHubAdapter    *hubAdapter.RoomHub
+		prometheusMetrics *metricsAdapter.PrometheusMetrics
+		// released after the hub has drained its connections
+		closers []web.ShutdownHook
 	)
 	{
 		ulidGenerator = idAdapter.NewULIDGenerator()
-		roomsManager = dummy.NewRoomsAccess(ulidGenerator)
+		prometheusMetrics = metricsAdapter.NewPrometheusMetrics()
+		metrics = prometheusMetrics
+		rateLimiter = ratelimitAdapter.NewInMemoryRateLimiter()
+		switch {
+		case strings.HasPrefix(conf.DB, "postgres://"), strings.HasPrefix(conf.DB, "postgresql://"):
+			db, err := postgresAdapter.Open(ctx, conf.DB)
+			if err != nil {
+				return nil, err
+			}
+			closers = append(closers, func(ctx context.Context) error { return db.Close() })
+			roomsManager = postgresAdapter.NewRoomsAccess(db, ulidGenerator)
+			messagesManager = postgresAdapter.NewMessagesAccess(db, ulidGenerator)
+			usersManager = postgresAdapter.NewUsersAccess(db, ulidGenerator)
+			sanctionsManager = postgresAdapter.NewSanctionsAccess(db)
+		case len(conf.DB) > 0:
+			db, err := sqliteAdapter.Open(ctx, conf.DB)
+			if err != nil {
+				return nil, err
+			}
+			closers = append(closers, func(ctx context.Context) error { return db.Close() })
+			roomsManager = sqliteAdapter.NewRoomsAccess(db, ulidGenerator)
+			messagesManager = sqliteAdapter.NewMessagesAccess(db, ulidGenerator)
+			usersManager = sqliteAdapter.NewUsersAccess(db, ulidGenerator)
+			sanctionsManager = sqliteAdapter.NewSanctionsAccess(db)
+		default:
+			dummyRooms := dummy.NewRoomsAccess(ulidGenerator)
+			roomsManager = dummyRooms
+			messagesManager = dummy.NewMessagesAccess(ulidGenerator, dummyRooms)
+			usersManager = dummy.NewUsersAccess(ulidGenerator)
+			sanctionsManager = dummy.NewSanctionsAccess(dummyRooms)
+		}
+		if len(conf.RedisURL) > 0 {
+			redisOpts, err := redis.ParseURL(conf.RedisURL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid redis url: %w", err)
+			}
+			client := redis.NewClient(redisOpts)
+			if err := client.Ping(ctx).Err(); err != nil {
+				return nil, fmt.Errorf("failed to connect redis: %w", err)
+			}
+			closers = append(closers, func(ctx context.Context) error { return client.Close() })
+			messageBus = busAdapter.NewRedisBus(client)
+		} else {
+			messageBus = busAdapter.NewInProcessBus()
+		}
+		roomHubAdapter = hubAdapter.NewRoomHub(
+			ulidGenerator,
+			messageBus,
+			hubAdapter.OptionQueueSize(conf.WebSocket.QueueSize),
+			hubAdapter.OptionMetrics(metrics),
+		)
+		roomHub = roomHubAdapter
+		passwordHasher = passwordAdapter.NewBcryptHasher(passwordAdapter.DefaultCost)
+		tokenManager = tokenAdapter.NewJWTManager(tokenSecret, conf.Token.TTL)
 	}
 
 	// interactors
@@ -116,13 +248,59 @@ func server() (*web.Server, error) {
 		listRoomsInteractor  interactor.ListRoomsInteractor
 		getRoomInteractor    interactor.GetRoomInteractor
 		createRoomInteractor interactor.CreateRoomInteractor
+		updateRoomInteractor interactor.UpdateRoomInteractor
 		deleteRoomInteractor interactor.DeleteRoomInteractor
+
+		connectRoomInteractor    interactor.ConnectRoomInteractor
+		disconnectRoomInteractor interactor.DisconnectRoomInteractor
+		postMessageInteractor    interactor.PostMessageInteractor
+		listMessagesInteractor   interactor.ListMessagesInteractor
+		replayMessagesInteractor interactor.ReplayMessagesInteractor
+		removeMessageInteractor  interactor.RemoveMessageInteractor
+
+		enterRoomInteractor        interactor.EnterRoomInteractor
+		listRoomMembersInteractor  interactor.ListRoomMembersInteractor
+		addRoomMemberInteractor    interactor.AddRoomMemberInteractor
+		removeRoomMemberInteractor interactor.RemoveRoomMemberInteractor
+
+		kickMemberInteractor     interactor.KickMemberInteractor
+		sanctionMemberInteractor interactor.SanctionMemberInteractor
+		liftSanctionInteractor   interactor.LiftSanctionInteractor
+
+		createUserInteractor       interactor.CreateUserInteractor
+		authenticateUserInteractor interactor.AuthenticateUserInteractor
+
+		issueTokenInteractor        interactor.IssueTokenInteractor
+		authenticateTokenInteractor interactor.AuthenticateTokenInteractor
 	)
 	{
 		listRoomsInteractor = interactor.NewListRoomsInteractor(roomsManager)
 		getRoomInteractor = interactor.NewGetRoomInteractor(roomsManager)
 		createRoomInteractor = interactor.NewCreateRoomInteractor(roomsManager)
-		deleteRoomInteractor = interactor.NewDeleteRoomInteractor(roomsManager)
+		updateRoomInteractor = interactor.NewUpdateRoomInteractor(roomsManager, roomHub)
+		deleteRoomInteractor = interactor.NewDeleteRoomInteractor(roomsManager, roomHub)
+
+		connectRoomInteractor = interactor.NewConnectRoomInteractor(roomHub)
+		disconnectRoomInteractor = interactor.NewDisconnectRoomInteractor(roomHub)
+		postMessageInteractor = interactor.NewPostMessageInteractor(messagesManager, sanctionsManager, roomHub, metrics)
+		listMessagesInteractor = interactor.NewListMessagesInteractor(roomsManager, sanctionsManager, messagesManager)
+		replayMessagesInteractor = interactor.NewReplayMessagesInteractor(messagesManager)
+		removeMessageInteractor = interactor.NewRemoveMessageInteractor(roomsManager, messagesManager)
+
+		enterRoomInteractor = interactor.NewEnterRoomInteractor(roomsManager, sanctionsManager)
+		listRoomMembersInteractor = interactor.NewListRoomMembersInteractor(roomsManager, sanctionsManager)
+		addRoomMemberInteractor = interactor.NewAddRoomMemberInteractor(roomsManager, usersManager, sanctionsManager)
+		removeRoomMemberInteractor = interactor.NewRemoveRoomMemberInteractor(roomsManager, roomHub)
+
+		kickMemberInteractor = interactor.NewKickMemberInteractor(roomsManager, roomHub)
+		sanctionMemberInteractor = interactor.NewSanctionMemberInteractor(roomsManager, usersManager, sanctionsManager, roomHub)
+		liftSanctionInteractor = interactor.NewLiftSanctionInteractor(roomsManager, sanctionsManager)
+
+		createUserInteractor = interactor.NewCreateUserInteractor(usersManager, passwordHasher)
+		authenticateUserInteractor = interactor.NewAuthenticateUserInteractor(usersManager, passwordHasher)
+
+		issueTokenInteractor = interactor.NewIssueTokenInteractor(tokenManager)
+		authenticateTokenInteractor = interactor.NewAuthenticateTokenInteractor(usersManager, tokenManager)
 	}
 
 	// routes
@@ -131,13 +309,114 @@ func server() (*web.Server, error) {
 		handlers.NewHealthGetHandler(),
 	)
 	r = append(r, health...)
+	metricsRoutes := routes.NewMetricsRoutes(
+		handlers.NewMetricsGetHandler(prometheusMetrics.Handler()),
+	)
+	r = append(r, metricsRoutes...)
+	users := routes.NewUsersRoutes(
+		handlers.NewCreateUserHandler(createUserInteractor),
+	)
+	r = append(r, users...)
+	auth := routes.NewAuthRoutes(
+		middlewares.NewBasicAuthenticator(authenticateUserInteractor),
+		handlers.NewIssueTokenHandler(issueTokenInteractor),
+	)
+	r = append(r, auth...)
+	authenticate := middlewares.NewAuthenticator(authenticateUserInteractor, authenticateTokenInteractor)
 	rooms := routes.NewRoomsRoutes(
+		authenticate,
 		handlers.NewListRoomsHandler(listRoomsInteractor),
 		handlers.NewGetRoomHandler(getRoomInteractor),
 		handlers.NewCreateRoomHandler(createRoomInteractor),
+		handlers.NewUpdateRoomHandler(updateRoomInteractor),
 		handlers.NewDeleteRoomHandler(deleteRoomInteractor),
-	)
+		handlers.NewStreamRoomMessagesHandler(
+			enterRoomInteractor,
+			connectRoomInteractor,
+			disconnectRoomInteractor,
+			postMessageInteractor,
+			replayMessagesInteractor,
+			kickMemberInteractor,
+			sanctionMemberInteractor,
+			handlers.OptionReadBufferSize(conf.WebSocket.ReadBufferSize),
+			handlers.OptionWriteBufferSize(conf.WebSocket.WriteBufferSize),
+			handlers.OptionFrameRateLimit(rateLimiter, port.RateLimit{
+				Rate:  conf.RateLimit.Frames.Rate,
+				Burst: conf.RateLimit.Frames.Burst,
+			}),
+		),
+		handlers.NewListMessagesHandler(listMessagesInteractor),
+		handlers.NewRemoveMessageHandler(removeMessageInteractor),
+		handlers.NewListRoomMembersHandler(listRoomMembersInteractor),
+		handlers.NewAddRoomMemberHandler(addRoomMemberInteractor),
+		handlers.NewRemoveRoomMemberHandler(removeRoomMemberInteractor),
+	).WithCredential()
 	r = append(r, rooms...)
+	moderation := routes.NewModerationRoutes(
+		authenticate,
+		handlers.NewKickMemberHandler(kickMemberInteractor),
+		handlers.NewSanctionMemberHandler(sanctionMemberInteractor, entity.SanctionTypeBan),
+		handlers.NewLiftSanctionHandler(liftSanctionInteractor, entity.SanctionTypeBan),
+		handlers.NewSanctionMemberHandler(sanctionMemberInteractor, entity.SanctionTypeMute),
+		handlers.NewLiftSanctionHandler(liftSanctionInteractor, entity.SanctionTypeMute),
+	).WithCredential()
+	r = append(r, moderation...)
 
-	return web.NewGinServer(r...), nil
+	for key, rate := range conf.RateLimit.Routes {
+		if !rate.Enabled() {
+			continue
+		}
+		method, path, err := config.ParseRoute(key)
+		if err != nil {
+			return nil, err
+		}
+		route := r.Find(method, path)
+		if route == nil {
+			return nil, fmt.Errorf("route of rate limit is not found: %s", key)
+		}
+		limiter := middlewares.NewRateLimiter(rateLimiter, port.RateLimit{
+			Rate:  rate.Rate,
+			Burst: rate.Burst,
+		})
+		route.Use(limiter)
+	}
+	if rate := conf.RateLimit.Credentials; rate.Enabled() {
+		limiter := middlewares.NewCredentialRateLimiter(rateLimiter, port.RateLimit{
+			Rate:  rate.Rate,
+			Burst: rate.Burst,
+		})
+		for _, route := range r {
+			if route.Credential() {
+				// shared by the routes, so that guesses cannot be spread over them
+				route.UseFirst(limiter)
+			}
+		}
+	}
+
+	server := web.NewGinServer(ulidGenerator, metrics, r...)
+	server.OnShutdown(roomHubAdapter.Shutdown)
+	server.OnShutdown(closers...)
+	// flushes the spans ended while the resources above were released
+	server.OnShutdown(tracerProvider.Shutdown)
+
+	return server, nil
+}
+
+// tlsConfig serves the configured certificate, which is reloaded until the server shuts down.
+func tlsConfig(ctx context.Context, server *web.Server, conf *config.TLSConfig) (*tls.Config, error) {
+	certs, err := web.NewCertReloader(conf.Cert, conf.Key)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf, err := web.NewTLSConfig(certs, conf.ClientCA)
+	if err != nil {
+		return nil, err
+	}
+	watchCtx, stopWatch := context.WithCancel(context.WithoutCancel(ctx))
+	go certs.Watch(watchCtx, conf.ReloadInterval)
+	server.OnShutdown(func(ctx context.Context) error {
+		stopWatch()
+		return nil
+	})
+	return tlsConf, nil
 }
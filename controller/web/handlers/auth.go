@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/mkaiho/go-ws-sample/entity"
 	"github.com/mkaiho/go-ws-sample/usecase"
 	"github.com/mkaiho/go-ws-sample/util"
 )
@@ -14,15 +17,32 @@ var ErrNoAuthValue = errors.New("no auth value")
 var ErrInvalidAuthValue = errors.New("invalid auth header value")
 var ErrNotSupportedAuthType = errors.New("not supported auth type")
 
+type AuthType int
+
+const (
+	AuthTypeBasic AuthType = iota
+	AuthTypeBearer
+)
+
+const (
+	// AccessTokenQueryKey is the query parameter carrying a bearer token.
+	AccessTokenQueryKey = "access_token"
+	// AccessTokenProtocol is the Sec-WebSocket-Protocol value followed by a bearer token.
+	// Browsers cannot set headers on WebSocket upgrades, so they send "access_token, <token>" instead.
+	AccessTokenProtocol = "access_token"
+)
+
 type Auth struct {
-	User     string `json:"user"`
-	Password string `json:"password"`
+	Type     AuthType `json:"type"`
+	User     string   `json:"user,omitempty"`
+	Password string   `json:"password,omitempty"`
+	Token    string   `json:"token,omitempty"`
 }
 
 func GetAuthInfo(gc *gin.Context) (*Auth, error) {
 	hValue := gc.Request.Header.Get("Authorization")
 	if len(hValue) == 0 {
-		return nil, ErrNoAuthValue
+		return getAccessTokenAuthInfo(gc)
 	}
 	hValues := strings.SplitN(hValue, " ", 2)
 	if len(hValues) != 2 {
@@ -35,7 +55,9 @@ func GetAuthInfo(gc *gin.Context) (*Auth, error) {
 	default:
 		return nil, ErrNotSupportedAuthType
 	case "Basic":
-		return getBasicAuthInfo(authValue)
+		return getBasicAuthInfo(gc.Request.Context(), authValue)
+	case "Bearer":
+		return getBearerAuthInfo(authValue)
 	}
 }
 
@@ -58,19 +80,35 @@ func IsAuthError(e error) bool {
 	return false
 }
 
-func getBasicAuthInfo(authValue string) (*Auth, error) {
-	logger := util.GLogger()
+type authUserContextKey struct{}
+
+// AuthUserFromContext returns the user resolved by the auth middleware.
+func AuthUserFromContext(ctx context.Context) (*entity.User, error) {
+	if v, ok := ctx.Value(authUserContextKey{}).(*entity.User); ok {
+		return v, nil
+	}
+	return nil, usecase.ErrNoAuthUser
+}
+
+func NewContextWithAuthUser(ctx context.Context, user *entity.User) context.Context {
+	return context.WithValue(ctx, authUserContextKey{}, user)
+}
+
+func getBasicAuthInfo(ctx context.Context, authValue string) (*Auth, error) {
+	logger := util.FromContext(ctx)
 	dec, err := base64.StdEncoding.DecodeString(authValue)
 	if err != nil {
 		logger.Error(err, "failed to decode auth value")
 		return nil, ErrInvalidAuthValue
 	}
-	decValues := strings.Split(string(dec), ":")
+	// passwords may contain colons, user names may not
+	decValues := strings.SplitN(string(dec), ":", 2)
 	if len(decValues) != 2 {
 		return nil, ErrInvalidAuthValue
 	}
 
 	auth := &Auth{
+		Type:     AuthTypeBasic,
 		User:     decValues[0],
 		Password: decValues[1],
 	}
@@ -80,3 +118,28 @@ func getBasicAuthInfo(authValue string) (*Auth, error) {
 
 	return auth, nil
 }
+
+func getBearerAuthInfo(authValue string) (*Auth, error) {
+	if len(authValue) == 0 {
+		return nil, ErrInvalidAuthValue
+	}
+	return &Auth{
+		Type:  AuthTypeBearer,
+		Token: authValue,
+	}, nil
+}
+
+func getAccessTokenAuthInfo(gc *gin.Context) (*Auth, error) {
+	if token, ok := gc.GetQuery(AccessTokenQueryKey); ok {
+		return getBearerAuthInfo(token)
+	}
+	if websocket.IsWebSocketUpgrade(gc.Request) {
+		protocols := websocket.Subprotocols(gc.Request)
+		for i, protocol := range protocols {
+			if protocol == AccessTokenProtocol && i+1 < len(protocols) {
+				return getBearerAuthInfo(protocols[i+1])
+			}
+		}
+	}
+	return nil, ErrNoAuthValue
+}
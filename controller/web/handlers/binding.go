@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	validatorlib "github.com/go-playground/validator/v10"
 )
 
@@ -15,7 +16,7 @@ func ShouldBind[T any](gc *gin.Context, obj T) error {
 	if err := gc.ShouldBindUri(obj); err != nil && !errors.As(err, vErr) {
 		return err
 	}
-	if err := gc.ShouldBind(obj); err != nil && !errors.As(err, vErr) {
+	if err := shouldBindBody(gc, obj); err != nil && !errors.As(err, vErr) {
 		return err
 	}
 	if validator != nil {
@@ -24,3 +25,11 @@ func ShouldBind[T any](gc *gin.Context, obj T) error {
 
 	return nil
 }
+
+func shouldBindBody(gc *gin.Context, obj any) error {
+	// gin falls back to form binding for content types it does not know
+	if gc.ContentType() == MIMEMergePatchJSON {
+		return gc.ShouldBindWith(obj, binding.JSON)
+	}
+	return gc.ShouldBind(obj)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+)
+
+// roomETag is the entity tag of a room, which changes whenever the room is updated.
+func roomETag(room *entity.Room) string {
+	return strconv.Quote(strconv.Itoa(room.Version))
+}
+
+// ifMatchVersion returns the room version required by an If-Match value,
+// or nil when any version is accepted.
+// Only a single strong entity tag can match, other values return ErrVersionConflict.
+func ifMatchVersion(ifMatch string) (*int, error) {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if len(ifMatch) == 0 || ifMatch == "*" {
+		return nil, nil
+	}
+	unquoted, err := strconv.Unquote(ifMatch)
+	if err != nil {
+		return nil, usecase.ErrVersionConflict
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil {
+		return nil, usecase.ErrVersionConflict
+	}
+	return &version, nil
+}
+
+// noneMatch reports whether an If-None-Match value allows sending the representation tagged with etag.
+func noneMatch(ifNoneMatch string, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return false
+		}
+	}
+	return true
+}
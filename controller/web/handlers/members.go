@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/interactor"
+)
+
+type MemberResponseDetail struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+func newMemberResponseDetail(member *entity.Member) *MemberResponseDetail {
+	return &MemberResponseDetail{
+		ID:   member.User.ID.String(),
+		Name: member.User.Name,
+		Role: member.Role.String(),
+	}
+}
+
+// List
+type (
+	ListRoomMembersRequest struct {
+		RoomID string `json:"room_id" uri:"room_id" validate:"required,max=26"`
+	}
+	ListRoomMembersResponse struct {
+		Members []*MemberResponseDetail `json:"members"`
+	}
+	ListRoomMembersHandler struct {
+		members interactor.ListRoomMembersInteractor
+	}
+)
+
+func NewListRoomMembersHandler(members interactor.ListRoomMembersInteractor) *ListRoomMembersHandler {
+	return &ListRoomMembersHandler{
+		members: members,
+	}
+}
+
+func (h *ListRoomMembersHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	var req ListRoomMembersRequest
+	if err := ShouldBind(gc, &req); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	out, err := h.members.List(ctx, &interactor.ListRoomMembersInput{
+		RoomID: entity.ID(req.RoomID),
+		UserID: authUser.ID,
+	})
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrForbidden) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	res := ListRoomMembersResponse{
+		Members: []*MemberResponseDetail{},
+	}
+	for _, member := range out.Members {
+		res.Members = append(res.Members, newMemberResponseDetail(member))
+	}
+	gc.JSON(http.StatusOK, res)
+}
+
+// Add
+type (
+	AddRoomMemberRequest struct {
+		RoomID string `json:"room_id" uri:"room_id" validate:"required,max=26"`
+		UserID string `json:"user_id" validate:"required,max=26"`
+		Role   string `json:"role" validate:"omitempty,oneof=member moderator"`
+	}
+	AddRoomMemberResponse struct {
+		Member *MemberResponseDetail `json:"member"`
+	}
+	AddRoomMemberHandler struct {
+		members interactor.AddRoomMemberInteractor
+	}
+)
+
+func NewAddRoomMemberHandler(members interactor.AddRoomMemberInteractor) *AddRoomMemberHandler {
+	return &AddRoomMemberHandler{
+		members: members,
+	}
+}
+
+func (h *AddRoomMemberHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	var req AddRoomMemberRequest
+	if err := ShouldBind(gc, &req); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	out, err := h.members.Add(ctx, &interactor.AddRoomMemberInput{
+		RoomID:  entity.ID(req.RoomID),
+		UserID:  entity.ID(req.UserID),
+		Role:    entity.Role(req.Role),
+		ActorID: authUser.ID,
+	})
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrAlreadyExistsEntity) || errors.Is(err, usecase.ErrForbidden) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	res := AddRoomMemberResponse{
+		Member: newMemberResponseDetail(out.Member),
+	}
+	gc.JSON(http.StatusCreated, res)
+}
+
+// Remove
+type (
+	RemoveRoomMemberRequest struct {
+		RoomID string `json:"room_id" uri:"room_id" validate:"required,max=26"`
+		UserID string `json:"user_id" uri:"user_id" validate:"required,max=26"`
+	}
+	RemoveRoomMemberResponse struct{}
+	RemoveRoomMemberHandler  struct {
+		members interactor.RemoveRoomMemberInteractor
+	}
+)
+
+func NewRemoveRoomMemberHandler(members interactor.RemoveRoomMemberInteractor) *RemoveRoomMemberHandler {
+	return &RemoveRoomMemberHandler{
+		members: members,
+	}
+}
+
+func (h *RemoveRoomMemberHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	var req RemoveRoomMemberRequest
+	if err := ShouldBind(gc, &req); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	_, err = h.members.Remove(ctx, &interactor.RemoveRoomMemberInput{
+		RoomID:  entity.ID(req.RoomID),
+		UserID:  entity.ID(req.UserID),
+		ActorID: authUser.ID,
+	})
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrForbidden) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
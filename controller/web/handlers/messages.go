@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/interactor"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+type UserResponseDetail struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type MessageResponseDetail struct {
+	ID             string              `json:"id"`
+	RoomID         string              `json:"room_id"`
+	Body           string              `json:"body"`
+	PostedDatetime *time.Time          `json:"posted_datetime,omitempty"`
+	PostedBy       *UserResponseDetail `json:"posted_by,omitempty"`
+}
+
+func newMessageResponseDetail(message *entity.PostMessage) *MessageResponseDetail {
+	detail := &MessageResponseDetail{
+		ID:             message.ID.String(),
+		RoomID:         message.RoomID.String(),
+		Body:           message.Body,
+		PostedDatetime: message.PostedDatetime,
+	}
+	if message.PostedBy != nil {
+		detail.PostedBy = &UserResponseDetail{
+			ID:   message.PostedBy.ID.String(),
+			Name: message.PostedBy.Name,
+		}
+	}
+	return detail
+}
+
+// List history
+type (
+	ListMessagesRequest struct {
+		RoomID string `json:"room_id" uri:"room_id" validate:"required,max=26"`
+		Cursor string `json:"cursor" form:"cursor" validate:"omitempty,max=26"`
+		Limit  int    `json:"limit" form:"limit" validate:"omitempty,min=1,max=100"`
+	}
+	ListMessagesResponse struct {
+		Messages   []*MessageResponseDetail `json:"messages"`
+		NextCursor *string                  `json:"next_cursor,omitempty"`
+	}
+	ListMessagesHandler struct {
+		messages interactor.ListMessagesInteractor
+	}
+)
+
+func NewListMessagesHandler(messages interactor.ListMessagesInteractor) *ListMessagesHandler {
+	return &ListMessagesHandler{
+		messages: messages,
+	}
+}
+
+func (h *ListMessagesHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	var req ListMessagesRequest
+	if err := ShouldBind(gc, &req); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	input := interactor.ListMessagesInput{
+		RoomID: entity.ID(req.RoomID),
+		Limit:  req.Limit,
+		UserID: authUser.ID,
+	}
+	if len(req.Cursor) > 0 {
+		input.Cursor = util.ToPointer(entity.ID(req.Cursor))
+	}
+	out, err := h.messages.List(ctx, &input)
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrForbidden) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	res := ListMessagesResponse{
+		Messages: []*MessageResponseDetail{},
+	}
+	for _, message := range out.Messages {
+		res.Messages = append(res.Messages, newMessageResponseDetail(message))
+	}
+	if out.NextCursor != nil {
+		res.NextCursor = util.ToPointer(out.NextCursor.String())
+	}
+	gc.JSON(http.StatusOK, res)
+}
+
+// Remove
+type (
+	RemoveMessageRequest struct {
+		RoomID    string `json:"room_id" uri:"room_id" validate:"required,max=26"`
+		MessageID string `json:"message_id" uri:"message_id" validate:"required,max=26"`
+	}
+	RemoveMessageHandler struct {
+		messages interactor.RemoveMessageInteractor
+	}
+)
+
+func NewRemoveMessageHandler(messages interactor.RemoveMessageInteractor) *RemoveMessageHandler {
+	return &RemoveMessageHandler{
+		messages: messages,
+	}
+}
+
+func (h *RemoveMessageHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	var req RemoveMessageRequest
+	if err := ShouldBind(gc, &req); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	_, err = h.messages.Remove(ctx, &interactor.RemoveMessageInput{
+		RoomID:    entity.ID(req.RoomID),
+		MessageID: entity.ID(req.MessageID),
+		ActorID:   authUser.ID,
+	})
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrForbidden) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
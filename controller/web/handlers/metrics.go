@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Get metrics
+type (
+	MetricsGetHandler struct {
+		h http.Handler
+	}
+)
+
+// NewMetricsGetHandler serves metrics through h, which writes them in the exposition format of the collector.
+func NewMetricsGetHandler(h http.Handler) *MetricsGetHandler {
+	return &MetricsGetHandler{
+		h: h,
+	}
+}
+
+func (h *MetricsGetHandler) Handle(gc *gin.Context) {
+	h.h.ServeHTTP(gc.Writer, gc.Request)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/interactor"
+)
+
+type SanctionResponseDetail struct {
+	UserID    string     `json:"user_id"`
+	Type      string     `json:"type"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Kick
+type (
+	KickMemberRequest struct {
+		RoomID string `json:"room_id" uri:"room_id" validate:"required,max=26"`
+		UserID string `json:"user_id" validate:"required,max=26"`
+	}
+	KickMemberHandler struct {
+		members interactor.KickMemberInteractor
+	}
+)
+
+func NewKickMemberHandler(members interactor.KickMemberInteractor) *KickMemberHandler {
+	return &KickMemberHandler{
+		members: members,
+	}
+}
+
+func (h *KickMemberHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	var req KickMemberRequest
+	if err := ShouldBind(gc, &req); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	_, err = h.members.Kick(ctx, &interactor.KickMemberInput{
+		RoomID:  entity.ID(req.RoomID),
+		UserID:  entity.ID(req.UserID),
+		ActorID: authUser.ID,
+	})
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrForbidden) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+// Sanction
+type (
+	SanctionMemberRequest struct {
+		RoomID string `json:"room_id" uri:"room_id" validate:"required,max=26"`
+		UserID string `json:"user_id" validate:"required,max=26"`
+		// ExpiresAt is when the sanction is lifted by itself, it lasts until it is lifted when omitted.
+		ExpiresAt *time.Time `json:"expires_at" validate:"omitempty,gt"`
+	}
+	SanctionMemberResponse struct {
+		Sanction *SanctionResponseDetail `json:"sanction"`
+	}
+	// SanctionMemberHandler imposes the sanction of its type, a ban or a mute, on a user in a room.
+	SanctionMemberHandler struct {
+		sanctions interactor.SanctionMemberInteractor
+		typ       entity.SanctionType
+	}
+)
+
+func NewSanctionMemberHandler(sanctions interactor.SanctionMemberInteractor, typ entity.SanctionType) *SanctionMemberHandler {
+	return &SanctionMemberHandler{
+		sanctions: sanctions,
+		typ:       typ,
+	}
+}
+
+func (h *SanctionMemberHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	var req SanctionMemberRequest
+	if err := ShouldBind(gc, &req); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	out, err := h.sanctions.Sanction(ctx, &interactor.SanctionMemberInput{
+		RoomID:    entity.ID(req.RoomID),
+		UserID:    entity.ID(req.UserID),
+		Type:      h.typ,
+		ExpiresAt: req.ExpiresAt,
+		ActorID:   authUser.ID,
+	})
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrForbidden) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	res := SanctionMemberResponse{
+		Sanction: &SanctionResponseDetail{
+			UserID:    out.Sanction.UserID.String(),
+			Type:      out.Sanction.Type.String(),
+			ExpiresAt: out.Sanction.ExpiresAt,
+		},
+	}
+	gc.JSON(http.StatusCreated, res)
+}
+
+// Lift
+type (
+	LiftSanctionRequest struct {
+		RoomID string `json:"room_id" uri:"room_id" validate:"required,max=26"`
+		UserID string `json:"user_id" uri:"user_id" validate:"required,max=26"`
+	}
+	// LiftSanctionHandler lifts the sanction of its type, a ban or a mute, from a user in a room.
+	LiftSanctionHandler struct {
+		sanctions interactor.LiftSanctionInteractor
+		typ       entity.SanctionType
+	}
+)
+
+func NewLiftSanctionHandler(sanctions interactor.LiftSanctionInteractor, typ entity.SanctionType) *LiftSanctionHandler {
+	return &LiftSanctionHandler{
+		sanctions: sanctions,
+		typ:       typ,
+	}
+}
+
+func (h *LiftSanctionHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	var req LiftSanctionRequest
+	if err := ShouldBind(gc, &req); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	_, err = h.sanctions.Lift(ctx, &interactor.LiftSanctionInput{
+		RoomID:  entity.ID(req.RoomID),
+		UserID:  entity.ID(req.UserID),
+		Type:    h.typ,
+		ActorID: authUser.ID,
+	})
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrForbidden) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+)
+
+// MIMEMergePatchJSON is the content type of a JSON merge patch (RFC 7396).
+const MIMEMergePatchJSON = "application/merge-patch+json"
+
+// PatchString is a string field of a JSON merge patch.
+// Set is false when the field is absent, and Null is true when the field is null.
+type PatchString struct {
+	Set   bool
+	Null  bool
+	Value string
+}
+
+func (p *PatchString) UnmarshalJSON(data []byte) error {
+	p.Set = true
+	if string(data) == "null" {
+		p.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &p.Value)
+}
+
+// Ptr returns nil when the field is absent, a pointer to nil when it is null,
+// and a pointer to the value otherwise.
+func (p PatchString) Ptr() **string {
+	if !p.Set {
+		return nil
+	}
+	var v *string
+	if !p.Null {
+		v = &p.Value
+	}
+	return &v
+}
@@ -8,20 +8,27 @@ import (
 	"github.com/mkaiho/go-ws-sample/entity"
 	"github.com/mkaiho/go-ws-sample/usecase"
 	"github.com/mkaiho/go-ws-sample/usecase/interactor"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
 )
 
 type RoomResponseDetail struct {
 	ID          string  `json:"id"`
 	Name        string  `json:"name"`
 	Description *string `json:"description,omitempty"`
+	Version     int     `json:"version"`
 }
 
 // List
 type (
 	ListRoomsRequest struct {
+		Query  string `json:"q" form:"q" validate:"omitempty,max=20"`
+		Sort   string `json:"sort" form:"sort" validate:"omitempty,oneof=created name"`
+		Cursor string `json:"cursor" form:"cursor" validate:"omitempty,max=256"`
+		Limit  int    `json:"limit" form:"limit" validate:"omitempty,min=1,max=100"`
 	}
 	ListRoomsResponse struct {
-		Rooms []*RoomResponseDetail `json:"rooms"`
+		Rooms      []*RoomResponseDetail `json:"rooms"`
+		NextCursor *string               `json:"next_cursor,omitempty"`
 	}
 	ListRoomsHandler struct {
 		rooms interactor.ListRoomsInteractor
@@ -42,21 +49,33 @@ func (h *ListRoomsHandler) Handle(gc *gin.Context) {
 		return
 	}
 
-	out, err := h.rooms.List(ctx, &interactor.ListRoomsInput{})
+	input := interactor.ListRoomsInput{
+		Query: req.Query,
+		Sort:  port.RoomsSortKey(req.Sort),
+		Limit: req.Limit,
+	}
+	if len(req.Cursor) > 0 {
+		input.Cursor = &req.Cursor
+	}
+	out, err := h.rooms.List(ctx, &input)
 	if err != nil {
 		gErr := gc.Error(err)
-		if errors.Is(err, usecase.ErrNotFoundEntity) {
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrInvalidCursor) {
 			gErr.SetType(gin.ErrorTypePublic)
 		}
 		return
 	}
 
-	var res ListRoomsResponse
+	res := ListRoomsResponse{
+		Rooms:      []*RoomResponseDetail{},
+		NextCursor: out.NextCursor,
+	}
 	for _, room := range out.Rooms {
 		res.Rooms = append(res.Rooms, &RoomResponseDetail{
 			ID:          room.ID.String(),
 			Name:        room.Name,
 			Description: room.Description,
+			Version:     room.Version,
 		})
 	}
 	gc.JSON(http.StatusOK, res)
@@ -65,7 +84,8 @@ func (h *ListRoomsHandler) Handle(gc *gin.Context) {
 // Get
 type (
 	GetRoomRequest struct {
-		ID string `json:"id" uri:"room_id" validate:"required,max=26"`
+		ID          string `json:"id" uri:"room_id" validate:"required,max=26"`
+		IfNoneMatch string `json:"-" header:"If-None-Match"`
 	}
 	GetRoomResponse struct {
 		Room *RoomResponseDetail `json:"room"`
@@ -100,11 +120,19 @@ func (h *GetRoomHandler) Handle(gc *gin.Context) {
 		return
 	}
 
+	etag := roomETag(out.Room)
+	gc.Header("ETag", etag)
+	if !noneMatch(req.IfNoneMatch, etag) {
+		gc.Status(http.StatusNotModified)
+		return
+	}
+
 	res := GetRoomResponse{
 		Room: &RoomResponseDetail{
 			ID:          out.Room.ID.String(),
 			Name:        out.Room.Name,
 			Description: out.Room.Description,
+			Version:     out.Room.Version,
 		},
 	}
 	gc.JSON(http.StatusOK, res)
@@ -137,10 +165,16 @@ func (h *CreateRoomHandler) Handle(gc *gin.Context) {
 		gc.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
 
 	out, err := h.rooms.Create(ctx, &interactor.CreateRoomInput{
 		Name:        req.Name,
 		Description: req.Description,
+		Owner:       authUser,
 	})
 	if err != nil {
 		gErr := gc.Error(err)
@@ -150,20 +184,95 @@ func (h *CreateRoomHandler) Handle(gc *gin.Context) {
 		return
 	}
 
+	gc.Header("ETag", roomETag(out.Room))
 	res := CreateRoomResponse{
 		Room: &RoomResponseDetail{
 			ID:          out.Room.ID.String(),
 			Name:        out.Room.Name,
 			Description: out.Room.Description,
+			Version:     out.Room.Version,
 		},
 	}
 	gc.JSON(http.StatusCreated, res)
 }
 
+// Update
+type (
+	// UpdateRoomRequest is a JSON merge patch, fields absent from it are left unchanged.
+	UpdateRoomRequest struct {
+		ID          string      `json:"id" uri:"room_id" validate:"required,max=26"`
+		Name        PatchString `json:"name" validate:"omitempty,min=1,max=20"`
+		Description PatchString `json:"description" validate:"omitempty,min=1,max=64"`
+		IfMatch     string      `json:"-" header:"If-Match"`
+	}
+	UpdateRoomResponse struct {
+		Room *RoomResponseDetail `json:"room"`
+	}
+	UpdateRoomHandler struct {
+		rooms interactor.UpdateRoomInteractor
+	}
+)
+
+func NewUpdateRoomHandler(rooms interactor.UpdateRoomInteractor) *UpdateRoomHandler {
+	return &UpdateRoomHandler{
+		rooms: rooms,
+	}
+}
+
+func (h *UpdateRoomHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	var req UpdateRoomRequest
+	if err := ShouldBind(gc, &req); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+	version, err := ifMatchVersion(req.IfMatch)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	input := interactor.UpdateRoomInput{
+		ID:          entity.ID(req.ID),
+		Description: req.Description.Ptr(),
+		Version:     version,
+		ActorID:     authUser.ID,
+	}
+	if req.Name.Set {
+		input.Name = &req.Name.Value
+	}
+	out, err := h.rooms.Update(ctx, &input)
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrVersionConflict) || errors.Is(err, usecase.ErrForbidden) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	gc.Header("ETag", roomETag(out.Room))
+	res := UpdateRoomResponse{
+		Room: &RoomResponseDetail{
+			ID:          out.Room.ID.String(),
+			Name:        out.Room.Name,
+			Description: out.Room.Description,
+			Version:     out.Room.Version,
+		},
+	}
+	gc.JSON(http.StatusOK, res)
+}
+
 // Delete
 type (
 	DeleteRoomRequest struct {
-		ID string `json:"id" uri:"room_id" validate:"required,max=26"`
+		ID      string `json:"id" uri:"room_id" validate:"required,max=26"`
+		IfMatch string `json:"-" header:"If-Match"`
 	}
 	DeleteRoomResponse struct{}
 	DeleteRoomHandler  struct {
@@ -185,12 +294,25 @@ func (h *DeleteRoomHandler) Handle(gc *gin.Context) {
 		return
 	}
 
-	_, err := h.rooms.Delete(ctx, &interactor.DeleteRoomInput{
-		ID: entity.ID(req.ID),
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+	version, err := ifMatchVersion(req.IfMatch)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	_, err = h.rooms.Delete(ctx, &interactor.DeleteRoomInput{
+		ID:      entity.ID(req.ID),
+		Version: version,
+		ActorID: authUser.ID,
 	})
 	if err != nil {
 		gErr := gc.Error(err)
-		if errors.Is(err, usecase.ErrNotFoundEntity) {
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrVersionConflict) || errors.Is(err, usecase.ErrForbidden) {
 			gErr.SetType(gin.ErrorTypePublic)
 		}
 		return
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-ws-sample/usecase/interactor"
+)
+
+// Issue
+type (
+	IssueTokenResponse struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	IssueTokenHandler struct {
+		tokens interactor.IssueTokenInteractor
+	}
+)
+
+func NewIssueTokenHandler(tokens interactor.IssueTokenInteractor) *IssueTokenHandler {
+	return &IssueTokenHandler{
+		tokens: tokens,
+	}
+}
+
+func (h *IssueTokenHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	out, err := h.tokens.Issue(ctx, &interactor.IssueTokenInput{
+		User: authUser,
+	})
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	res := IssueTokenResponse{
+		AccessToken: out.Token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(out.ExpiresAt).Seconds()),
+	}
+	gc.Header("Cache-Control", "no-store")
+	gc.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/interactor"
+)
+
+// Create
+type (
+	CreateUserRequest struct {
+		Name     string `json:"name" validate:"required,max=20,excludes=:"`
+		Password string `json:"password" validate:"required,min=8,max=72"`
+	}
+	CreateUserResponse struct {
+		User *UserResponseDetail `json:"user"`
+	}
+	CreateUserHandler struct {
+		users interactor.CreateUserInteractor
+	}
+)
+
+func NewCreateUserHandler(users interactor.CreateUserInteractor) *CreateUserHandler {
+	return &CreateUserHandler{
+		users: users,
+	}
+}
+
+func (h *CreateUserHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	var req CreateUserRequest
+	if err := ShouldBind(gc, &req); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	out, err := h.users.Create(ctx, &interactor.CreateUserInput{
+		Name:     req.Name,
+		Password: req.Password,
+	})
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrAlreadyExistsEntity) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	res := CreateUserResponse{
+		User: &UserResponseDetail{
+			ID:   out.User.ID.String(),
+			Name: out.User.Name,
+		},
+	}
+	gc.JSON(http.StatusCreated, res)
+}
@@ -1,5 +1,45 @@
 package handlers
 
-import validatorlib "github.com/go-playground/validator/v10"
+import (
+	"reflect"
+	"strings"
 
-var validator = validatorlib.New()
+	validatorlib "github.com/go-playground/validator/v10"
+)
+
+var validator = newValidator()
+
+func newValidator() *validatorlib.Validate {
+	v := validatorlib.New()
+	// patch fields are validated like pointers, so omitempty skips absent and null fields
+	// and a set empty string is still checked by min
+	v.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		if p, ok := field.Interface().(PatchString); ok && p.Set && !p.Null {
+			return &p.Value
+		}
+		return nil
+	}, PatchString{})
+	v.RegisterStructValidation(validateUpdateRoomRequest, UpdateRoomRequest{})
+	// fields are reported by the names clients send them with
+	v.RegisterTagNameFunc(fieldName)
+	return v
+}
+
+// validateUpdateRoomRequest rejects a null name, a room always has a name
+// so it can be replaced but not removed. A null description clears it.
+func validateUpdateRoomRequest(sl validatorlib.StructLevel) {
+	req := sl.Current().Interface().(UpdateRoomRequest)
+	if req.Name.Null {
+		sl.ReportError(req.Name, "name", "Name", "required", "")
+	}
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"uri", "header", "form", "json"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if len(name) > 0 && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	validatorlib "github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldBind_UpdateRoomRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name string
+		body string
+		// wantFields are the fields and tags of the validation errors
+		wantFields map[string]string
+		wantDesc   **string
+	}{
+		{
+			name: "accept an absent name and description",
+			body: `{}`,
+		},
+		{
+			name: "accept a new name",
+			body: `{"name":"renamed"}`,
+		},
+		{
+			name: "reject an empty name",
+			body: `{"name":""}`,
+			wantFields: map[string]string{
+				"name": "min",
+			},
+		},
+		{
+			name: "reject a null name",
+			body: `{"name":null}`,
+			wantFields: map[string]string{
+				"name": "required",
+			},
+		},
+		{
+			name: "reject a too long name",
+			body: `{"name":"` + strings.Repeat("a", 21) + `"}`,
+			wantFields: map[string]string{
+				"name": "max",
+			},
+		},
+		{
+			name: "reject an empty description",
+			body: `{"description":""}`,
+			wantFields: map[string]string{
+				"description": "min",
+			},
+		},
+		{
+			name:     "clear the description with null",
+			body:     `{"description":null}`,
+			wantDesc: new(*string),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/rooms/01HNZ0000000000000000000AA", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", MIMEMergePatchJSON)
+			gc, _ := gin.CreateTestContext(httptest.NewRecorder())
+			gc.Request = req
+			gc.Params = gin.Params{{Key: "room_id", Value: "01HNZ0000000000000000000AA"}}
+
+			var got UpdateRoomRequest
+			err := ShouldBind(gc, &got)
+			if tt.wantFields != nil {
+				var vErrs validatorlib.ValidationErrors
+				require.ErrorAs(t, err, &vErrs)
+				fields := map[string]string{}
+				for _, fErr := range vErrs {
+					fields[fErr.Field()] = fErr.Tag()
+				}
+				assert.Equal(t, tt.wantFields, fields)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDesc, got.Description.Ptr())
+		})
+	}
+}
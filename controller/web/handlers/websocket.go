@@ -1,8 +1,489 @@
 package handlers
 
-import "github.com/gorilla/websocket"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/mkaiho/go-ws-sample/controller/ws/protocol"
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/interactor"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+const (
+	wsWriteWait = 10 * time.Second
+	// wsMaxPendingEvents bounds the live events held back while missed messages are replayed.
+	wsMaxPendingEvents = 1024
+	// wsCloseRoomDeleted is the close code sent when the room of the connection is deleted.
+	wsCloseRoomDeleted = 4404
+	// wsCloseKicked is the close code sent when the user of the connection is kicked or banned from the room.
+	wsCloseKicked = 4403
+)
+
+const (
+	defaultWebSocketReadBufferSize  = 1024
+	defaultWebSocketWriteBufferSize = 1024
+)
+
+type streamRoomMessagesOption interface {
+	apply(*StreamRoomMessagesHandler)
+}
+
+type ReadBufferSizeOption int
+
+func (o ReadBufferSizeOption) apply(h *StreamRoomMessagesHandler) {
+	if o > 0 {
+		h.upgrader.ReadBufferSize = int(o)
+	}
+}
+
+// OptionReadBufferSize sets the size in bytes of the buffer reading frames from a connection.
+func OptionReadBufferSize(v int) ReadBufferSizeOption {
+	return ReadBufferSizeOption(v)
+}
+
+type WriteBufferSizeOption int
+
+func (o WriteBufferSizeOption) apply(h *StreamRoomMessagesHandler) {
+	if o > 0 {
+		h.upgrader.WriteBufferSize = int(o)
+	}
+}
+
+// OptionWriteBufferSize sets the size in bytes of the buffer writing frames to a connection.
+func OptionWriteBufferSize(v int) WriteBufferSizeOption {
+	return WriteBufferSizeOption(v)
+}
+
+type FrameRateLimitOption struct {
+	limiter port.RateLimiter
+	limit   port.RateLimit
+}
+
+func (o FrameRateLimitOption) apply(h *StreamRoomMessagesHandler) {
+	if o.limit.Rate > 0 {
+		h.frameLimiter = o.limiter
+		h.frameLimit = o.limit
+	}
+}
+
+// OptionFrameRateLimit limits the frames each connection sends, the frames over the limit are rejected.
+// A zero rate does not limit them.
+func OptionFrameRateLimit(limiter port.RateLimiter, limit port.RateLimit) FrameRateLimitOption {
+	return FrameRateLimitOption{limiter: limiter, limit: limit}
+}
+
+var _ port.RoomHubConn = (*wsConn)(nil)
+
+// wsConn adapts a websocket connection to port.RoomHubConn.
+// Both the hub's writer goroutine and the read loop replying to the client write frames,
+// websocket.Conn supports a single concurrent writer so writes are serialized.
+type wsConn struct {
+	mux  sync.Mutex
+	conn *websocket.Conn
+	// replaying holds live messages back in pending until the missed messages are replayed.
+	replaying bool
+	pending   []*port.RoomEvent
+	// replayed are the messages sent by the replay, a live message published during the replay
+	// may reach the connection after it ended and must not be sent twice.
+	// It is cleared by the first live message which was not replayed, the later ones are newer than the replay.
+	replayed map[entity.ID]struct{}
+}
+
+func (c *wsConn) Send(ctx context.Context, event *port.RoomEvent) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	// only messages are ordered after the replayed ones, room events are sent right away
+	if event.Type == port.RoomEventTypeMessageCreated && event.Message != nil {
+		if _, ok := c.replayed[event.Message.ID]; ok {
+			return nil
+		}
+		c.replayed = nil
+	}
+	if c.replaying && event.Type == port.RoomEventTypeMessageCreated {
+		if len(c.pending) >= wsMaxPendingEvents {
+			return errors.New("too many events are pending during replay")
+		}
+		c.pending = append(c.pending, event)
+		return nil
+	}
+	return c.sendLocked(event)
+}
+
+// endReplay sends the events held back during the replay except the replayed messages.
+func (c *wsConn) endReplay(replayed map[entity.ID]struct{}) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	caughtUp := false
+	for _, event := range c.pending {
+		if event.Message != nil {
+			if _, ok := replayed[event.Message.ID]; ok {
+				continue
+			}
+			caughtUp = true
+		}
+		if err := c.sendLocked(event); err != nil {
+			return err
+		}
+	}
+	c.pending = nil
+	c.replaying = false
+	if !caughtUp {
+		c.replayed = replayed
+	}
+	return nil
+}
+
+func (c *wsConn) sendLocked(event *port.RoomEvent) error {
+	switch event.Type {
+	case port.RoomEventTypeRoomUpdated:
+		return c.writeLocked(protocol.TypeRoomUpdated, "", event.RoomID.String(), protocol.NewRoomUpdatedPayload(event.Room))
+	case port.RoomEventTypeRoomDeleted:
+		return c.writeLocked(protocol.TypeRoomDeleted, "", event.RoomID.String(), &protocol.RoomDeletedPayload{ID: event.RoomID.String()})
+	default:
+		return c.writeLocked(protocol.TypeMessageCreated, "", event.RoomID.String(), protocol.NewMessageCreatedPayload(event.Message))
+	}
+}
+
+func (c *wsConn) write(typ protocol.Type, id string, roomID string, payload any) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.writeLocked(typ, id, roomID, payload)
+}
+
+func (c *wsConn) writeLocked(typ protocol.Type, id string, roomID string, payload any) error {
+	frame, err := protocol.Encode(typ, id, roomID, payload)
+	if err != nil {
+		return err
+	}
+	if err := c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait)); err != nil {
+		return err
+	}
+	return c.conn.WriteMessage(websocket.TextMessage, frame)
+}
+
+func (c *wsConn) Close(ctx context.Context, reason port.CloseReason) error {
+	code := websocket.CloseNormalClosure
+	switch reason {
+	case port.CloseReasonSlowConsumer:
+		code = websocket.CloseTryAgainLater
+	case port.CloseReasonGoingAway:
+		code = websocket.CloseGoingAway
+	case port.CloseReasonRoomDeleted:
+		code = wsCloseRoomDeleted
+	case port.CloseReasonKicked:
+		code = wsCloseKicked
+	}
+	deadline := time.Now().Add(wsWriteWait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	msg := websocket.FormatCloseMessage(code, reason.String())
+	if err := c.conn.WriteControl(websocket.CloseMessage, msg, deadline); err != nil && !errors.Is(err, websocket.ErrCloseSent) {
+		c.conn.Close()
+		return err
+	}
+	return c.conn.Close()
+}
+
+// Stream
+type (
+	StreamRoomMessagesRequest struct {
+		RoomID string `json:"room_id" uri:"room_id" validate:"required,max=26"`
+		// Since is the last message the client has received, the messages after it are replayed.
+		Since string `json:"since" form:"since" validate:"omitempty,max=26"`
+	}
+	StreamRoomMessagesHandler struct {
+		rooms      interactor.EnterRoomInteractor
+		connect    interactor.ConnectRoomInteractor
+		disconnect interactor.DisconnectRoomInteractor
+		messages   interactor.PostMessageInteractor
+		replay     interactor.ReplayMessagesInteractor
+		kick       interactor.KickMemberInteractor
+		sanction   interactor.SanctionMemberInteractor
+		upgrader   websocket.Upgrader
+		// frameLimiter is nil when frames are not limited.
+		frameLimiter port.RateLimiter
+		frameLimit   port.RateLimit
+	}
+)
+
+func NewStreamRoomMessagesHandler(
+	rooms interactor.EnterRoomInteractor,
+	connect interactor.ConnectRoomInteractor,
+	disconnect interactor.DisconnectRoomInteractor,
+	messages interactor.PostMessageInteractor,
+	replay interactor.ReplayMessagesInteractor,
+	kick interactor.KickMemberInteractor,
+	sanction interactor.SanctionMemberInteractor,
+	options ...streamRoomMessagesOption,
+) *StreamRoomMessagesHandler {
+	h := &StreamRoomMessagesHandler{
+		rooms:      rooms,
+		connect:    connect,
+		disconnect: disconnect,
+		messages:   messages,
+		replay:     replay,
+		kick:       kick,
+		sanction:   sanction,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  defaultWebSocketReadBufferSize,
+			WriteBufferSize: defaultWebSocketWriteBufferSize,
+			// selected only when the client authenticates through Sec-WebSocket-Protocol
+			Subprotocols: []string{AccessTokenProtocol},
+		},
+	}
+	for _, opt := range options {
+		opt.apply(h)
+	}
+	return h
+}
+
+func (h *StreamRoomMessagesHandler) Handle(gc *gin.Context) {
+	ctx := gc.Request.Context()
+	logger := util.FromContext(ctx)
+	var req StreamRoomMessagesRequest
+	if err := ShouldBind(gc, &req); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	authUser, err := AuthUserFromContext(ctx)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	out, err := h.rooms.Enter(ctx, &interactor.EnterRoomInput{
+		RoomID: entity.ID(req.RoomID),
+		UserID: authUser.ID,
+	})
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrForbidden) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+	roomID := out.Room.ID
+	user := out.Member.User
+
+	conn, err := h.upgrader.Upgrade(gc.Writer, gc.Request, nil)
+	if err != nil {
+		// the upgrader has already replied to the client
+		logger.Error(err, "failed to upgrade connection", "roomID", roomID)
+		return
+	}
+	defer conn.Close()
+
+	// live events are held back from the connection until the missed messages are replayed
+	ws := &wsConn{
+		conn:      conn,
+		replaying: len(req.Since) > 0,
+	}
+	connected, err := h.connect.Connect(ctx, &interactor.ConnectRoomInput{
+		RoomID: roomID,
+		UserID: user.ID,
+		Conn:   ws,
+	})
+	if err != nil {
+		logger.Error(err, "failed to connect room", "roomID", roomID)
+		return
+	}
+	connID := connected.ConnID
+	// the logs of the session are correlated by the request ID and the connection
+	logger = logger.WithValues("roomID", roomID, "connID", connID)
+	ctx = util.NewContextWithLogger(ctx, logger)
+	logger.Info("session started")
+	defer func() {
+		if _, err := h.disconnect.Disconnect(ctx, &interactor.DisconnectRoomInput{
+			RoomID: roomID,
+			ConnID: connID,
+		}); err != nil {
+			logger.Error(err, "failed to disconnect room")
+		}
+		logger.Info("session ended")
+	}()
+
+	if ws.replaying {
+		if err := h.replayMissed(ctx, ws, roomID, entity.ID(req.Since)); err != nil {
+			logger.Error(err, "failed to replay messages")
+			return
+		}
+	}
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Error(err, "failed to read message")
+			}
+			return
+		}
+
+		var pErr *protocol.Error
+		if limited, err := h.frameLimited(ctx, connID); err != nil {
+			logger.Error(err, "failed to limit frame rate")
+			pErr = protocol.NewError(protocol.ErrorCodeInternal, "failed to process frame")
+		} else if limited != nil {
+			// the frame is dropped, its ID tells the client which one
+			if envelope, err := protocol.Decode(data); err == nil {
+				limited = limited.WithID(envelope.ID)
+			}
+			pErr = limited
+		} else if messageType != websocket.TextMessage {
+			pErr = protocol.NewError(protocol.ErrorCodeInvalidFrame, "frame must be text")
+		} else if err := h.receive(ctx, ws, roomID, connID, user, data); err != nil && !errors.As(err, &pErr) {
+			logger.Error(err, "failed to process frame")
+			pErr = protocol.NewError(protocol.ErrorCodeInternal, "failed to process frame")
+		}
+		// errors are reported to the client, the stream stays open
+		if pErr != nil {
+			if err := ws.write(protocol.TypeError, pErr.ID(), roomID.String(), pErr); err != nil {
+				logger.Error(err, "failed to send error")
+				return
+			}
+		}
+	}
+}
+
+// frameLimited returns a rate_limited error when the connection exceeds its frame rate.
+func (h *StreamRoomMessagesHandler) frameLimited(ctx context.Context, connID entity.ID) (*protocol.Error, error) {
+	if h.frameLimiter == nil {
+		return nil, nil
+	}
+	out, err := h.frameLimiter.Allow(ctx, &port.AllowRateInput{
+		Key:   "ws:" + connID.String(),
+		Limit: h.frameLimit,
+	})
+	if err != nil || out.Allowed {
+		return nil, err
+	}
+	return protocol.NewError(
+		protocol.ErrorCodeRateLimited,
+		fmt.Sprintf("too many frames, retry after %dms", out.RetryAfter.Milliseconds()),
+	), nil
+}
+
+// replayMissed sends the messages posted after since, then switches the connection to live delivery.
+func (h *StreamRoomMessagesHandler) replayMissed(ctx context.Context, ws *wsConn, roomID entity.ID, since entity.ID) error {
+	replayed := make(map[entity.ID]struct{})
+	for {
+		out, err := h.replay.Replay(ctx, &interactor.ReplayMessagesInput{
+			RoomID: roomID,
+			Since:  since,
+		})
+		if err != nil {
+			return err
+		}
+		for _, message := range out.Messages {
+			if err := ws.write(protocol.TypeMessageCreated, "", roomID.String(), protocol.NewMessageCreatedPayload(message)); err != nil {
+				return err
+			}
+			replayed[message.ID] = struct{}{}
+			since = message.ID
+		}
+		if !out.HasMore {
+			break
+		}
+	}
+	return ws.endReplay(replayed)
+}
+
+// receive processes a frame from the client and acknowledges it.
+// A *protocol.Error is returned for frames rejected by the protocol.
+func (h *StreamRoomMessagesHandler) receive(
+	ctx context.Context,
+	ws *wsConn,
+	roomID entity.ID,
+	connID entity.ID,
+	user *entity.User,
+	data []byte,
+) error {
+	envelope, err := protocol.Decode(data)
+	if err != nil {
+		return err
+	}
+	if len(envelope.RoomID) > 0 && envelope.RoomID != roomID.String() {
+		return protocol.NewError(protocol.ErrorCodeRoomMismatch, "room_id does not match the stream").WithID(envelope.ID)
+	}
+
+	switch envelope.Type {
+	case protocol.TypeMessagePost:
+		var payload protocol.MessagePostPayload
+		if err := protocol.DecodePayload(envelope, &payload); err != nil {
+			return err
+		}
+		out, err := h.messages.Post(ctx, &interactor.PostMessageInput{
+			RoomID:         roomID,
+			ConnID:         connID,
+			Body:           payload.Body,
+			User:           user,
+			IdempotencyKey: payload.IdempotencyKey,
+		})
+		if errors.Is(err, usecase.ErrMuted) {
+			return protocol.NewError(protocol.ErrorCodeMuted, "user is muted in the room").WithID(envelope.ID)
+		}
+		if err != nil {
+			util.FromContext(ctx).Error(err, "failed to post message")
+			return protocol.NewError(protocol.ErrorCodeInternal, "failed to post message").WithID(envelope.ID)
+		}
+		return ws.write(protocol.TypeAck, envelope.ID, roomID.String(), &protocol.AckPayload{
+			MessageID: out.Message.ID.String(),
+		})
+	case protocol.TypeMemberKick:
+		var payload protocol.MemberKickPayload
+		if err := protocol.DecodePayload(envelope, &payload); err != nil {
+			return err
+		}
+		_, err := h.kick.Kick(ctx, &interactor.KickMemberInput{
+			RoomID:  roomID,
+			UserID:  entity.ID(payload.UserID),
+			ActorID: user.ID,
+		})
+		if err != nil {
+			return moderationError(ctx, envelope, err)
+		}
+	case protocol.TypeMemberBan, protocol.TypeMemberMute:
+		var payload protocol.MemberSanctionPayload
+		if err := protocol.DecodePayload(envelope, &payload); err != nil {
+			return err
+		}
+		typ := entity.SanctionTypeBan
+		if envelope.Type == protocol.TypeMemberMute {
+			typ = entity.SanctionTypeMute
+		}
+		_, err := h.sanction.Sanction(ctx, &interactor.SanctionMemberInput{
+			RoomID:    roomID,
+			UserID:    entity.ID(payload.UserID),
+			Type:      typ,
+			ExpiresAt: payload.ExpiresAt,
+			ActorID:   user.ID,
+		})
+		if err != nil {
+			return moderationError(ctx, envelope, err)
+		}
+	}
+
+	return ws.write(protocol.TypeAck, envelope.ID, roomID.String(), nil)
+}
+
+// moderationError replies to a moderation command the interactor refused.
+func moderationError(ctx context.Context, envelope *protocol.Envelope, err error) *protocol.Error {
+	switch {
+	case errors.Is(err, usecase.ErrForbidden):
+		return protocol.NewError(protocol.ErrorCodeForbidden, "role in the room does not allow it").WithID(envelope.ID)
+	case errors.Is(err, usecase.ErrNotFoundEntity):
+		return protocol.NewError(protocol.ErrorCodeNotFound, "user is not found").WithID(envelope.ID)
+	default:
+		util.FromContext(ctx).Error(err, "failed to moderate", "type", envelope.Type)
+		return protocol.NewError(protocol.ErrorCodeInternal, "failed to moderate").WithID(envelope.ID)
+	}
 }
@@ -0,0 +1,287 @@
+// The stream is tested through the middlewares, which import this package.
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/mkaiho/go-ws-sample/adapter/bus"
+	"github.com/mkaiho/go-ws-sample/adapter/dummy"
+	"github.com/mkaiho/go-ws-sample/adapter/hub"
+	"github.com/mkaiho/go-ws-sample/adapter/id"
+	"github.com/mkaiho/go-ws-sample/adapter/metrics"
+	"github.com/mkaiho/go-ws-sample/adapter/ratelimit"
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+	"github.com/mkaiho/go-ws-sample/controller/web/middlewares"
+	"github.com/mkaiho/go-ws-sample/controller/ws/protocol"
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/interactor"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testUserHeader = "X-Test-User"
+
+// replayFunc lets a test act while the missed messages are replayed.
+type replayFunc func(ctx context.Context, input *interactor.ReplayMessagesInput) (*interactor.ReplayMessagesOutput, error)
+
+func (f replayFunc) Replay(ctx context.Context, input *interactor.ReplayMessagesInput) (*interactor.ReplayMessagesOutput, error) {
+	return f(ctx, input)
+}
+
+type streamServer struct {
+	*httptest.Server
+	room     *entity.Room
+	users    map[string]*entity.User
+	messages *dummy.MessagesAccess
+	post     interactor.PostMessageInteractor
+	// beforeReplay is called once before the first replay of the missed messages.
+	beforeReplay func(ctx context.Context)
+}
+
+// newStreamServer serves the stream of a room whose members are alice and bob,
+// each connection is allowed frameBurst frames.
+func newStreamServer(t *testing.T, frameBurst int) *streamServer {
+	t.Helper()
+	ctx := context.Background()
+	idGenerator := id.NewULIDGenerator()
+	rooms := dummy.NewRoomsAccess(idGenerator)
+	messages := dummy.NewMessagesAccess(idGenerator, rooms)
+	users := dummy.NewUsersAccess(idGenerator)
+	sanctions := dummy.NewSanctionsAccess(rooms)
+	roomHub := hub.NewRoomHub(idGenerator, bus.NewInProcessBus())
+
+	s := &streamServer{
+		users:    map[string]*entity.User{},
+		messages: messages,
+		post:     interactor.NewPostMessageInteractor(messages, sanctions, roomHub, metrics.NopMetrics{}),
+	}
+	for _, name := range []string{"alice", "bob", "carol"} {
+		out, err := users.Create(ctx, &port.CreateUserInput{Name: name, PasswordHash: []byte("hash")})
+		require.NoError(t, err)
+		s.users[name] = out.User
+	}
+	created, err := rooms.Create(ctx, &port.CreateRoomInput{Name: "room", Owner: s.users["alice"]})
+	require.NoError(t, err)
+	_, err = rooms.AddMember(ctx, &port.AddRoomMemberInput{RoomID: created.Room.ID, User: s.users["bob"], Role: entity.RoleMember})
+	require.NoError(t, err)
+	s.room = created.Room
+
+	var once sync.Once
+	replay := interactor.NewReplayMessagesInteractor(messages)
+	h := handlers.NewStreamRoomMessagesHandler(
+		interactor.NewEnterRoomInteractor(rooms, sanctions),
+		interactor.NewConnectRoomInteractor(roomHub),
+		interactor.NewDisconnectRoomInteractor(roomHub),
+		s.post,
+		replayFunc(func(ctx context.Context, input *interactor.ReplayMessagesInput) (*interactor.ReplayMessagesOutput, error) {
+			if s.beforeReplay != nil {
+				once.Do(func() { s.beforeReplay(ctx) })
+			}
+			return replay.Replay(ctx, input)
+		}),
+		interactor.NewKickMemberInteractor(rooms, roomHub),
+		interactor.NewSanctionMemberInteractor(rooms, users, sanctions, roomHub),
+		handlers.OptionFrameRateLimit(ratelimit.NewInMemoryRateLimiter(), port.RateLimit{Rate: 0.001, Burst: frameBurst}),
+	)
+
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.Use(gin.HandlerFunc(middlewares.Recovery()))
+	e.GET("/rooms/:room_id/messages", func(gc *gin.Context) {
+		if user, ok := s.users[gc.GetHeader(testUserHeader)]; ok {
+			gc.Request = gc.Request.WithContext(handlers.NewContextWithAuthUser(gc.Request.Context(), user))
+		}
+	}, h.Handle)
+	s.Server = httptest.NewServer(e)
+	t.Cleanup(s.Close)
+	// the connections are closed by the hub before the server
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		roomHub.Shutdown(ctx)
+	})
+	return s
+}
+
+// dial connects the user to the stream of the room, ping tells when the stream has joined it.
+func (s *streamServer) dial(t *testing.T, user string, roomID entity.ID, query string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(s.URL, "http") + "/rooms/" + roomID.String() + "/messages" + query
+	conn, res, err := websocket.DefaultDialer.Dial(url, http.Header{testUserHeader: {user}})
+	if err != nil {
+		return nil, res, err
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, res, nil
+}
+
+func sendFrame(t *testing.T, conn *websocket.Conn, typ protocol.Type, id string, payload any) {
+	t.Helper()
+	frame, err := protocol.Encode(typ, id, "", payload)
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, frame))
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) *protocol.Envelope {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	var envelope protocol.Envelope
+	require.NoError(t, json.Unmarshal(data, &envelope))
+	return &envelope
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) *protocol.MessageCreatedPayload {
+	t.Helper()
+	envelope := readFrame(t, conn)
+	require.Equal(t, protocol.TypeMessageCreated, envelope.Type)
+	var payload protocol.MessageCreatedPayload
+	require.NoError(t, json.Unmarshal(envelope.Payload, &payload))
+	return &payload
+}
+
+// ping waits for the ack of a ping, the stream has joined the room once it replies.
+func ping(t *testing.T, conn *websocket.Conn, id string) {
+	t.Helper()
+	sendFrame(t, conn, protocol.TypePing, id, nil)
+	envelope := readFrame(t, conn)
+	require.Equal(t, protocol.TypeAck, envelope.Type)
+	require.Equal(t, id, envelope.ID)
+}
+
+func TestStreamRoomMessagesHandler_Handle_Rejected(t *testing.T) {
+	s := newStreamServer(t, 10)
+	tests := []struct {
+		name       string
+		user       string
+		roomID     entity.ID
+		wantStatus int
+		wantCode   middlewares.ProblemCode
+	}{
+		{
+			name:       "return not found for an unknown room",
+			user:       "alice",
+			roomID:     "01HNZ0000000000000000000ZZ",
+			wantStatus: http.StatusNotFound,
+			wantCode:   middlewares.ProblemCodeNotFound,
+		},
+		{
+			name:       "return not found for a user who is not a member",
+			user:       "carol",
+			roomID:     s.room.ID,
+			wantStatus: http.StatusNotFound,
+			wantCode:   middlewares.ProblemCodeNotFound,
+		},
+		{
+			name:       "return unauthorized without an auth user",
+			user:       "mallory",
+			roomID:     s.room.ID,
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   middlewares.ProblemCodeNoAuthUser,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, res, err := s.dial(t, tt.user, tt.roomID, "")
+			require.ErrorIs(t, err, websocket.ErrBadHandshake)
+			defer res.Body.Close()
+			assert.Equal(t, tt.wantStatus, res.StatusCode)
+			var problem middlewares.Problem
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&problem))
+			assert.Equal(t, tt.wantCode, problem.Code)
+		})
+	}
+}
+
+func TestStreamRoomMessagesHandler_Handle_Broadcast(t *testing.T) {
+	s := newStreamServer(t, 10)
+	alice, res, err := s.dial(t, "alice", s.room.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+	bob, _, err := s.dial(t, "bob", s.room.ID, "")
+	require.NoError(t, err)
+	ping(t, alice, "ping-alice")
+	ping(t, bob, "ping-bob")
+
+	sendFrame(t, alice, protocol.TypeMessagePost, "post-1", &protocol.MessagePostPayload{Body: "hello", IdempotencyKey: "key-1"})
+	ack := readFrame(t, alice)
+	require.Equal(t, protocol.TypeAck, ack.Type)
+	assert.Equal(t, "post-1", ack.ID)
+	var ackPayload protocol.AckPayload
+	require.NoError(t, json.Unmarshal(ack.Payload, &ackPayload))
+
+	got := readMessage(t, bob)
+	assert.Equal(t, ackPayload.MessageID, got.ID)
+	assert.Equal(t, "hello", got.Body)
+	assert.Equal(t, s.users["alice"].ID.String(), got.PostedBy.ID)
+	// the sender is not sent its own message, its next frame replies to the ping
+	ping(t, alice, "ping-alice-2")
+}
+
+func TestStreamRoomMessagesHandler_Handle_Replay(t *testing.T) {
+	ctx := context.Background()
+	s := newStreamServer(t, 10)
+	var posted []entity.ID
+	postMessage := func(ctx context.Context, body string) {
+		out, err := s.post.Post(ctx, &interactor.PostMessageInput{
+			RoomID:         s.room.ID,
+			Body:           body,
+			User:           s.users["bob"],
+			IdempotencyKey: body,
+		})
+		require.NoError(t, err)
+		posted = append(posted, out.Message.ID)
+	}
+	for i := 1; i <= 3; i++ {
+		postMessage(ctx, fmt.Sprintf("missed-%d", i))
+	}
+	// a message posted while replaying is both replayed and delivered live, it is sent once
+	s.beforeReplay = func(ctx context.Context) {
+		postMessage(ctx, "during-replay")
+	}
+
+	alice, _, err := s.dial(t, "alice", s.room.ID, "?since="+posted[0].String())
+	require.NoError(t, err)
+	var got []string
+	for i := 0; i < 3; i++ {
+		got = append(got, readMessage(t, alice).Body)
+	}
+	assert.Equal(t, []string{"missed-2", "missed-3", "during-replay"}, got)
+
+	postMessage(ctx, "live")
+	assert.Equal(t, "live", readMessage(t, alice).Body)
+}
+
+func TestStreamRoomMessagesHandler_Handle_FrameRateLimited(t *testing.T) {
+	const burst = 3
+	s := newStreamServer(t, burst)
+	alice, _, err := s.dial(t, "alice", s.room.ID, "")
+	require.NoError(t, err)
+	for i := 1; i <= burst; i++ {
+		ping(t, alice, fmt.Sprintf("ping-%d", i))
+	}
+
+	sendFrame(t, alice, protocol.TypeMessagePost, "post-1", &protocol.MessagePostPayload{Body: "hello", IdempotencyKey: "key-1"})
+	got := readFrame(t, alice)
+	require.Equal(t, protocol.TypeError, got.Type)
+	assert.Equal(t, "post-1", got.ID)
+	var pErr protocol.Error
+	require.NoError(t, json.Unmarshal(got.Payload, &pErr))
+	assert.Equal(t, protocol.ErrorCodeRateLimited, pErr.Code)
+	// the limited frame was dropped
+	messages, err := s.messages.Find(context.Background(), &port.FindMessagesInput{RoomID: s.room.ID})
+	require.NoError(t, err)
+	assert.Empty(t, messages.Messages)
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/interactor"
+)
+
+// NewAuthenticator resolves the Basic credential or bearer token of the request into a user
+// and stores it in the request context.
+func NewAuthenticator(users interactor.AuthenticateUserInteractor, tokens interactor.AuthenticateTokenInteractor) handlers.Handler {
+	return authenticator(func(ctx context.Context, auth *handlers.Auth) (*entity.User, error) {
+		switch auth.Type {
+		case handlers.AuthTypeBearer:
+			out, err := tokens.Authenticate(ctx, &interactor.AuthenticateTokenInput{
+				Token: auth.Token,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return out.User, nil
+		default:
+			return authenticateBasic(ctx, users, auth)
+		}
+	})
+}
+
+// NewBasicAuthenticator is NewAuthenticator restricted to Basic credentials.
+func NewBasicAuthenticator(users interactor.AuthenticateUserInteractor) handlers.Handler {
+	return authenticator(func(ctx context.Context, auth *handlers.Auth) (*entity.User, error) {
+		if auth.Type != handlers.AuthTypeBasic {
+			return nil, handlers.ErrNotSupportedAuthType
+		}
+		return authenticateBasic(ctx, users, auth)
+	})
+}
+
+func authenticateBasic(ctx context.Context, users interactor.AuthenticateUserInteractor, auth *handlers.Auth) (*entity.User, error) {
+	out, err := users.Authenticate(ctx, &interactor.AuthenticateUserInput{
+		Name:     auth.User,
+		Password: auth.Password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.User, nil
+}
+
+func authenticator(authenticate func(ctx context.Context, auth *handlers.Auth) (*entity.User, error)) handlers.Handler {
+	return func(gc *gin.Context) {
+		ctx := gc.Request.Context()
+		auth, err := handlers.GetAuthInfo(gc)
+		if err != nil {
+			gc.Error(err).SetType(gin.ErrorTypePublic)
+			gc.Abort()
+			return
+		}
+
+		user, err := authenticate(ctx, auth)
+		if err != nil {
+			gErr := gc.Error(err)
+			if handlers.IsAuthError(err) {
+				gErr.SetType(gin.ErrorTypePublic)
+			}
+			gc.Abort()
+			return
+		}
+
+		gc.Request = gc.Request.WithContext(handlers.NewContextWithAuthUser(ctx, user))
+		gc.Next()
+	}
+}
@@ -3,6 +3,7 @@ package middlewares
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,12 +16,12 @@ func NewGinLogger() handlers.Handler {
 		start := time.Now()
 		path := c.Request.URL.Path
 		if raw := c.Request.URL.RawQuery; len(raw) > 0 {
-			path = path + "?" + raw
+			path = path + "?" + maskQuery(raw)
 		}
 
 		c.Next()
 
-		logger := util.GLogger().
+		logger := util.FromContext(c.Request.Context()).
 			WithValues("latency", fmt.Sprintf("%dµs", time.Since(start)/1000)).
 			WithValues("clientIP", c.ClientIP()).
 			WithValues("method", c.Request.Method).
@@ -34,3 +35,12 @@ func NewGinLogger() handlers.Handler {
 		logger.Info("accepted request")
 	}
 }
+
+func maskQuery(raw string) string {
+	query, err := url.ParseQuery(raw)
+	if err != nil || !query.Has(handlers.AccessTokenQueryKey) {
+		return raw
+	}
+	query.Set(handlers.AccessTokenQueryKey, "*")
+	return query.Encode()
+}
@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+)
+
+// unmatchedRoute labels the requests no route matched, so that arbitrary paths do not become labels.
+const unmatchedRoute = "unmatched"
+
+// NewMetricsRecorder records the count and latency of requests by route template and status.
+func NewMetricsRecorder(metrics port.Metrics) handlers.Handler {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if len(route) == 0 {
+			route = unmatchedRoute
+		}
+		metrics.ObserveHTTPRequest(c.Request.Context(), c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,140 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	validatorlib "github.com/go-playground/validator/v10"
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+	"github.com/mkaiho/go-ws-sample/usecase"
+)
+
+const (
+	MIMEProblemJSON = "application/problem+json"
+
+	// problemTypeBase prefixes the code of a problem to make its type URI.
+	problemTypeBase = "https://github.com/mkaiho/go-ws-sample/problems/"
+)
+
+// ProblemCode identifies the kind of a problem, clients can branch on it.
+type ProblemCode string
+
+const (
+	ProblemCodeValidationFailed    ProblemCode = "validation_failed"
+	ProblemCodeInvalidRequest      ProblemCode = "invalid_request"
+	ProblemCodeBadRequest          ProblemCode = "bad_request"
+	ProblemCodeInvalidCursor       ProblemCode = "invalid_cursor"
+	ProblemCodeNoAuthValue         ProblemCode = "no_auth_value"
+	ProblemCodeInvalidAuthValue    ProblemCode = "invalid_auth_value"
+	ProblemCodeUnsupportedAuthType ProblemCode = "unsupported_auth_type"
+	ProblemCodeNoAuthUser          ProblemCode = "no_auth_user"
+	ProblemCodeInvalidCredential   ProblemCode = "invalid_credential"
+	ProblemCodeForbidden           ProblemCode = "forbidden"
+	ProblemCodeNotFound            ProblemCode = "not_found"
+	ProblemCodeAlreadyExists       ProblemCode = "already_exists"
+	ProblemCodeVersionConflict     ProblemCode = "version_conflict"
+	ProblemCodeRateLimited         ProblemCode = "rate_limited"
+	ProblemCodeInternal            ProblemCode = "internal_error"
+)
+
+// Problem is an RFC 7807 problem details body.
+type Problem struct {
+	Type     string          `json:"type"`
+	Title    string          `json:"title"`
+	Status   int             `json:"status"`
+	Detail   string          `json:"detail,omitempty"`
+	Instance string          `json:"instance,omitempty"`
+	Code     ProblemCode     `json:"code"`
+	Errors   []*ProblemField `json:"errors,omitempty"`
+}
+
+// ProblemField is a request field failing validation.
+type ProblemField struct {
+	Field string `json:"field"`
+	// Rule is the validation rule the field failed, such as "required" or "max".
+	Rule   string `json:"rule"`
+	Detail string `json:"detail"`
+}
+
+// problemKinds maps the errors exposed to clients, the detail of a problem is the message of its error.
+var problemKinds = []struct {
+	err    error
+	status int
+	code   ProblemCode
+}{
+	{err: usecase.ErrInvalidCursor, status: http.StatusBadRequest, code: ProblemCodeInvalidCursor},
+	{err: handlers.ErrNoAuthValue, status: http.StatusUnauthorized, code: ProblemCodeNoAuthValue},
+	{err: handlers.ErrInvalidAuthValue, status: http.StatusUnauthorized, code: ProblemCodeInvalidAuthValue},
+	{err: handlers.ErrNotSupportedAuthType, status: http.StatusUnauthorized, code: ProblemCodeUnsupportedAuthType},
+	{err: usecase.ErrNoAuthUser, status: http.StatusUnauthorized, code: ProblemCodeNoAuthUser},
+	{err: usecase.ErrInvalidCredential, status: http.StatusUnauthorized, code: ProblemCodeInvalidCredential},
+	{err: usecase.ErrForbidden, status: http.StatusForbidden, code: ProblemCodeForbidden},
+	{err: usecase.ErrNotFoundEntity, status: http.StatusNotFound, code: ProblemCodeNotFound},
+	{err: usecase.ErrAlreadyExistsEntity, status: http.StatusConflict, code: ProblemCodeAlreadyExists},
+	{err: usecase.ErrVersionConflict, status: http.StatusPreconditionFailed, code: ProblemCodeVersionConflict},
+	{err: usecase.ErrRateLimited, status: http.StatusTooManyRequests, code: ProblemCodeRateLimited},
+}
+
+func newProblem(c *gin.Context, status int, code ProblemCode, detail string) *Problem {
+	return &Problem{
+		Type:     problemTypeBase + string(code),
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Code:     code,
+	}
+}
+
+// bindProblem describes a request rejected while binding.
+func bindProblem(c *gin.Context, err error) *Problem {
+	var vErrs validatorlib.ValidationErrors
+	if !errors.As(err, &vErrs) {
+		return newProblem(c, http.StatusBadRequest, ProblemCodeInvalidRequest, err.Error())
+	}
+	problem := newProblem(c, http.StatusBadRequest, ProblemCodeValidationFailed, "request has invalid fields")
+	for _, fErr := range vErrs {
+		problem.Errors = append(problem.Errors, &ProblemField{
+			Field:  fErr.Field(),
+			Rule:   fErr.Tag(),
+			Detail: fieldDetail(fErr),
+		})
+	}
+	return problem
+}
+
+// publicProblem describes an error the handlers chose to expose.
+func publicProblem(c *gin.Context, err error) *Problem {
+	for _, kind := range problemKinds {
+		if errors.Is(err, kind.err) {
+			return newProblem(c, kind.status, kind.code, kind.err.Error())
+		}
+	}
+	return newProblem(c, http.StatusBadRequest, ProblemCodeBadRequest, "")
+}
+
+func fieldDetail(fErr validatorlib.FieldError) string {
+	switch fErr.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fErr.Field())
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", fErr.Field(), fErr.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", fErr.Field(), fErr.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", fErr.Field(), fErr.Param())
+	default:
+		if len(fErr.Param()) > 0 {
+			return fmt.Sprintf("%s must satisfy %s=%s", fErr.Field(), fErr.Tag(), fErr.Param())
+		}
+		return fmt.Sprintf("%s must satisfy %s", fErr.Field(), fErr.Tag())
+	}
+}
+
+func abortWithProblem(c *gin.Context, problem *Problem) {
+	c.Abort()
+	c.Header("Content-Type", MIMEProblemJSON)
+	c.JSON(problem.Status, problem)
+}
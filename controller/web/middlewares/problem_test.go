@@ -0,0 +1,261 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveProblem serves the request with the handler behind Recovery and decodes the problem it responded.
+func serveProblem(t *testing.T, path string, handler gin.HandlerFunc, req *http.Request) (*httptest.ResponseRecorder, *Problem) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.Use(gin.HandlerFunc(Recovery()))
+	e.Handle(req.Method, path, handler)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var problem Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	return rec, &problem
+}
+
+func TestRecovery_Problem(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantCode   ProblemCode
+		wantDetail string
+	}{
+		{
+			name: "invalid cursor",
+			handler: func(gc *gin.Context) {
+				gc.Error(fmt.Errorf("failed to decode: %w", usecase.ErrInvalidCursor)).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   ProblemCodeInvalidCursor,
+			wantDetail: usecase.ErrInvalidCursor.Error(),
+		},
+		{
+			name: "no auth value",
+			handler: func(gc *gin.Context) {
+				gc.Error(handlers.ErrNoAuthValue).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   ProblemCodeNoAuthValue,
+			wantDetail: handlers.ErrNoAuthValue.Error(),
+		},
+		{
+			name: "invalid auth value",
+			handler: func(gc *gin.Context) {
+				gc.Error(handlers.ErrInvalidAuthValue).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   ProblemCodeInvalidAuthValue,
+			wantDetail: handlers.ErrInvalidAuthValue.Error(),
+		},
+		{
+			name: "unsupported auth type",
+			handler: func(gc *gin.Context) {
+				gc.Error(handlers.ErrNotSupportedAuthType).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   ProblemCodeUnsupportedAuthType,
+			wantDetail: handlers.ErrNotSupportedAuthType.Error(),
+		},
+		{
+			name: "no auth user",
+			handler: func(gc *gin.Context) {
+				gc.Error(usecase.ErrNoAuthUser).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   ProblemCodeNoAuthUser,
+			wantDetail: usecase.ErrNoAuthUser.Error(),
+		},
+		{
+			name: "invalid credential",
+			handler: func(gc *gin.Context) {
+				gc.Error(usecase.ErrInvalidCredential).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   ProblemCodeInvalidCredential,
+			wantDetail: usecase.ErrInvalidCredential.Error(),
+		},
+		{
+			name: "forbidden",
+			handler: func(gc *gin.Context) {
+				gc.Error(usecase.ErrForbidden).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusForbidden,
+			wantCode:   ProblemCodeForbidden,
+			wantDetail: usecase.ErrForbidden.Error(),
+		},
+		{
+			name: "not found",
+			handler: func(gc *gin.Context) {
+				gc.Error(usecase.ErrNotFoundEntity).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusNotFound,
+			wantCode:   ProblemCodeNotFound,
+			wantDetail: usecase.ErrNotFoundEntity.Error(),
+		},
+		{
+			name: "already exists",
+			handler: func(gc *gin.Context) {
+				gc.Error(usecase.ErrAlreadyExistsEntity).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusConflict,
+			wantCode:   ProblemCodeAlreadyExists,
+			wantDetail: usecase.ErrAlreadyExistsEntity.Error(),
+		},
+		{
+			name: "version conflict",
+			handler: func(gc *gin.Context) {
+				gc.Error(usecase.ErrVersionConflict).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+			wantCode:   ProblemCodeVersionConflict,
+			wantDetail: usecase.ErrVersionConflict.Error(),
+		},
+		{
+			name: "rate limited",
+			handler: func(gc *gin.Context) {
+				gc.Error(usecase.ErrRateLimited).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   ProblemCodeRateLimited,
+			wantDetail: usecase.ErrRateLimited.Error(),
+		},
+		{
+			name: "public error without a problem kind",
+			handler: func(gc *gin.Context) {
+				gc.Error(errors.New("unknown")).SetType(gin.ErrorTypePublic)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   ProblemCodeBadRequest,
+		},
+		{
+			name: "bind error without validation errors",
+			handler: func(gc *gin.Context) {
+				gc.Error(errors.New("invalid character")).SetType(gin.ErrorTypeBind)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   ProblemCodeInvalidRequest,
+			wantDetail: "invalid character",
+		},
+		{
+			name: "private error is not exposed",
+			handler: func(gc *gin.Context) {
+				gc.Error(errors.New("connection refused"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   ProblemCodeInternal,
+		},
+		{
+			name: "panic",
+			handler: func(gc *gin.Context) {
+				panic("unexpected")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   ProblemCodeInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/rooms/01HNZ0000000000000000000AA?limit=10", nil)
+			rec, got := serveProblem(t, "/rooms/:room_id", tt.handler, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, MIMEProblemJSON, rec.Header().Get("Content-Type"))
+			assert.Equal(t, &Problem{
+				Type:     problemTypeBase + string(tt.wantCode),
+				Title:    http.StatusText(tt.wantStatus),
+				Status:   tt.wantStatus,
+				Detail:   tt.wantDetail,
+				Instance: "/rooms/01HNZ0000000000000000000AA",
+				Code:     tt.wantCode,
+			}, got)
+		})
+	}
+}
+
+func TestRecovery_ValidationProblem(t *testing.T) {
+	type request struct {
+		ID    string `uri:"room_id" validate:"required,max=26"`
+		Token string `header:"X-Token" validate:"required"`
+		Name  string `json:"name" validate:"required,max=5"`
+		Role  string `json:"role" validate:"omitempty,oneof=member moderator"`
+		Body  string `json:"body" validate:"omitempty,min=2"`
+	}
+	handler := func(gc *gin.Context) {
+		var req request
+		if err := handlers.ShouldBind(gc, &req); err != nil {
+			gc.Error(err).SetType(gin.ErrorTypeBind)
+			return
+		}
+		gc.Status(http.StatusNoContent)
+	}
+	tests := []struct {
+		name       string
+		roomID     string
+		token      string
+		body       string
+		wantFields []*ProblemField
+	}{
+		{
+			name:   "report a missing field",
+			roomID: "01HNZ0000000000000000000AA",
+			token:  "token",
+			body:   `{}`,
+			wantFields: []*ProblemField{
+				{Field: "name", Rule: "required", Detail: "name is required"},
+			},
+		},
+		{
+			name:   "report every invalid field by the name clients send it with",
+			roomID: strings.Repeat("A", 27),
+			body:   `{"name":"alice-and-bob","role":"owner","body":"a"}`,
+			wantFields: []*ProblemField{
+				{Field: "room_id", Rule: "max", Detail: "room_id must be at most 26"},
+				{Field: "X-Token", Rule: "required", Detail: "X-Token is required"},
+				{Field: "name", Rule: "max", Detail: "name must be at most 5"},
+				{Field: "role", Rule: "oneof", Detail: "role must be one of member moderator"},
+				{Field: "body", Rule: "min", Detail: "body must be at least 2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/rooms/"+tt.roomID, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if len(tt.token) > 0 {
+				req.Header.Set("X-Token", tt.token)
+			}
+			rec, got := serveProblem(t, "/rooms/:room_id", handler, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, MIMEProblemJSON, rec.Header().Get("Content-Type"))
+			assert.Equal(t, &Problem{
+				Type:     problemTypeBase + string(ProblemCodeValidationFailed),
+				Title:    http.StatusText(http.StatusBadRequest),
+				Status:   http.StatusBadRequest,
+				Detail:   "request has invalid fields",
+				Instance: "/rooms/" + tt.roomID,
+				Code:     ProblemCodeValidationFailed,
+				Errors:   tt.wantFields,
+			}, got)
+		})
+	}
+}
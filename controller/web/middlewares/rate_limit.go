@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+)
+
+// NewRateLimiter limits the requests to the route of each authenticated user, or of each client IP otherwise.
+// Placed before the authentication, it always limits by client IP and counts the requests failing it too.
+// Requests over the limit are rejected with 429 and the seconds to wait in Retry-After.
+func NewRateLimiter(limiter port.RateLimiter, limit port.RateLimit) handlers.Handler {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		caller := "ip:" + c.ClientIP()
+		if user, err := handlers.AuthUserFromContext(ctx); err == nil {
+			caller = "user:" + user.ID.String()
+		}
+		allowRate(c, limiter, limit, c.Request.Method+" "+c.FullPath()+" "+caller)
+	}
+}
+
+// NewCredentialRateLimiter limits the requests carrying a password of each client IP, across all the routes it is placed on.
+// Placed before the authentication, it counts the failed attempts too and rejects guesses before they cost a password hash comparison.
+// The requests authenticated otherwise are not counted.
+func NewCredentialRateLimiter(limiter port.RateLimiter, limit port.RateLimit) handlers.Handler {
+	return func(c *gin.Context) {
+		if auth, err := handlers.GetAuthInfo(c); err != nil || auth.Type != handlers.AuthTypeBasic {
+			c.Next()
+			return
+		}
+		allowRate(c, limiter, limit, "credential ip:"+c.ClientIP())
+	}
+}
+
+// allowRate continues the request if the key is within the limit, or aborts it with 429.
+func allowRate(c *gin.Context, limiter port.RateLimiter, limit port.RateLimit, key string) {
+	out, err := limiter.Allow(c.Request.Context(), &port.AllowRateInput{
+		Key:   key,
+		Limit: limit,
+	})
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return
+	}
+	if !out.Allowed {
+		c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(out.RetryAfter)))
+		c.Error(usecase.ErrRateLimited).SetType(gin.ErrorTypePublic)
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// retryAfterSeconds rounds up, so that a client retrying on time is allowed.
+func retryAfterSeconds(d time.Duration) int {
+	return int(math.Max(1, math.Ceil(d.Seconds())))
+}
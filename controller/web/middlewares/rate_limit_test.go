@@ -0,0 +1,164 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	ratelimitAdapter "github.com/mkaiho/go-ws-sample/adapter/ratelimit"
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+	"github.com/mkaiho/go-ws-sample/controller/web/routes"
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/interactor"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRateLimiter_BadCredentials(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name string
+		// use places the limiter on the route
+		use                 func(route *routes.Route, limiter handlers.Handler)
+		wantStatuses        []int
+		wantCredentialCalls int
+	}{
+		{
+			name: "limited before the authentication",
+			use: func(route *routes.Route, limiter handlers.Handler) {
+				route.UseFirst(limiter)
+			},
+			wantStatuses: []int{
+				http.StatusUnauthorized,
+				http.StatusUnauthorized,
+				http.StatusUnauthorized,
+				http.StatusTooManyRequests,
+				http.StatusTooManyRequests,
+			},
+			wantCredentialCalls: 3,
+		},
+		{
+			name: "limited after the authentication",
+			use: func(route *routes.Route, limiter handlers.Handler) {
+				route.Use(limiter)
+			},
+			wantStatuses: []int{
+				http.StatusUnauthorized,
+				http.StatusUnauthorized,
+				http.StatusUnauthorized,
+				http.StatusUnauthorized,
+				http.StatusUnauthorized,
+			},
+			wantCredentialCalls: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := mocks.NewUsersReader(t)
+			users.On("GetCredential", mock.Anything, &port.GetUserCredentialInput{Name: "alice"}).
+				Return(&port.GetUserCredentialOutput{
+					User:         &entity.User{ID: "user-1", Name: "alice"},
+					PasswordHash: []byte("hash"),
+				}, nil).
+				Times(tt.wantCredentialCalls)
+			hasher := mocks.NewPasswordHasher(t)
+			hasher.On("Compare", mock.Anything, []byte("hash"), "wrong").
+				Return(usecase.ErrInvalidCredential).
+				Times(tt.wantCredentialCalls)
+
+			r := routes.NewAuthRoutes(
+				NewBasicAuthenticator(interactor.NewAuthenticateUserInteractor(users, hasher)),
+				handlers.NewIssueTokenHandler(nil),
+			)
+			route := r.Find(http.MethodPost, "/auth/token")
+			require.NotNil(t, route)
+			require.True(t, route.Credential())
+			tt.use(route, NewRateLimiter(ratelimitAdapter.NewInMemoryRateLimiter(), port.RateLimit{Rate: 0.001, Burst: 3}))
+
+			e := gin.New()
+			e.Use(gin.HandlerFunc(Recovery()))
+			var hs []gin.HandlerFunc
+			for _, h := range route.Handlers() {
+				hs = append(hs, gin.HandlerFunc(h))
+			}
+			e.Handle(route.Method(), route.Path(), hs...)
+
+			var statuses []int
+			for range tt.wantStatuses {
+				req := httptest.NewRequest(http.MethodPost, "/auth/token", nil)
+				req.SetBasicAuth("alice", "wrong")
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+				statuses = append(statuses, rec.Code)
+			}
+			assert.Equal(t, tt.wantStatuses, statuses)
+		})
+	}
+}
+
+func TestNewCredentialRateLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := mocks.NewUsersReader(t)
+	users.On("GetCredential", mock.Anything, &port.GetUserCredentialInput{Name: "alice"}).
+		Return(&port.GetUserCredentialOutput{
+			User:         &entity.User{ID: "user-1", Name: "alice"},
+			PasswordHash: []byte("hash"),
+		}, nil).
+		Times(3)
+	hasher := mocks.NewPasswordHasher(t)
+	hasher.On("Compare", mock.Anything, []byte("hash"), "wrong").
+		Return(usecase.ErrInvalidCredential).
+		Times(3)
+	authenticateUser := interactor.NewAuthenticateUserInteractor(users, hasher)
+
+	r := routes.NewAuthRoutes(
+		NewBasicAuthenticator(authenticateUser),
+		handlers.NewIssueTokenHandler(nil),
+	)
+	r = append(r, routes.NewRoomsRoutes(
+		NewAuthenticator(authenticateUser, interactor.NewAuthenticateTokenInteractor(nil, nil)),
+		handlers.NewListRoomsHandler(nil),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+	).WithCredential()...)
+	limiter := NewCredentialRateLimiter(ratelimitAdapter.NewInMemoryRateLimiter(), port.RateLimit{Rate: 0.001, Burst: 3})
+	e := gin.New()
+	e.Use(gin.HandlerFunc(Recovery()))
+	for _, route := range r {
+		require.True(t, route.Credential(), "%s %s", route.Method(), route.Path())
+		route.UseFirst(limiter)
+		var hs []gin.HandlerFunc
+		for _, h := range route.Handlers() {
+			hs = append(hs, gin.HandlerFunc(h))
+		}
+		e.Handle(route.Method(), route.Path(), hs...)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		basicAuth  bool
+		wantStatus int
+	}{
+		{name: "count a guess on the token", method: http.MethodPost, path: "/auth/token", basicAuth: true, wantStatus: http.StatusUnauthorized},
+		{name: "count a guess on the rooms", method: http.MethodGet, path: "/rooms", basicAuth: true, wantStatus: http.StatusUnauthorized},
+		{name: "count another guess on the token", method: http.MethodPost, path: "/auth/token", basicAuth: true, wantStatus: http.StatusUnauthorized},
+		{name: "limit a guess on the rooms after the ones on the token", method: http.MethodGet, path: "/rooms", basicAuth: true, wantStatus: http.StatusTooManyRequests},
+		{name: "limit a guess on the token after the ones on the rooms", method: http.MethodPost, path: "/auth/token", basicAuth: true, wantStatus: http.StatusTooManyRequests},
+		{name: "not limit a request without a password", method: http.MethodGet, path: "/rooms", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.basicAuth {
+			req.SetBasicAuth("alice", "wrong")
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, tt.wantStatus, rec.Code, tt.name)
+	}
+}
@@ -11,15 +11,14 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
-	"github.com/mkaiho/go-ws-sample/usecase"
 	"github.com/mkaiho/go-ws-sample/util"
 )
 
 func Recovery() handlers.Handler {
-	logger := util.GLogger().WithCallDepth(2)
 	return func(c *gin.Context) {
 		defer func() {
 			if p := recover(); p != nil {
+				logger := util.FromContext(c.Request.Context()).WithCallDepth(2)
 				var brokenPipe bool
 				if ne, ok := p.(*net.OpError); ok {
 					var se *os.SyscallError
@@ -59,31 +58,17 @@ func Recovery() handlers.Handler {
 					c.Error(pErr)
 					c.Abort()
 				} else {
-					c.AbortWithStatus(http.StatusInternalServerError)
+					abortWithProblem(c, newProblem(c, http.StatusInternalServerError, ProblemCodeInternal, ""))
 				}
+				return
 			}
 			if errMsgs := c.Errors.ByType(gin.ErrorTypeBind); len(errMsgs) > 0 {
-				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-					"message": errMsgs[0].Err.Error(),
-				})
+				abortWithProblem(c, bindProblem(c, errMsgs[0].Err))
 			} else if errMsgs := c.Errors.ByType(gin.ErrorTypePublic); len(errMsgs) > 0 {
-				var msg string
-				code := http.StatusBadRequest
-				if errors.Is(errMsgs[0].Err, usecase.ErrNotFoundEntity) {
-					code = http.StatusNotFound
-				} else if errors.Is(errMsgs[0].Err, usecase.ErrAlreadyExistsEntity) {
-					code = http.StatusConflict
-				} else if handlers.IsAuthError(errMsgs[0].Err) {
-					code = http.StatusUnauthorized
-					msg = errMsgs[0].Err.Error()
-				}
-
-				if len(msg) == 0 {
-					msg = http.StatusText(code)
-				}
-				c.AbortWithStatusJSON(code, gin.H{
-					"message": msg,
-				})
+				abortWithProblem(c, publicProblem(c, errMsgs[0].Err))
+			} else if len(c.Errors) > 0 && !c.Writer.Written() {
+				// the other errors are logged, their details are not exposed
+				abortWithProblem(c, newProblem(c, http.StatusInternalServerError, ProblemCodeInternal, ""))
 			}
 		}()
 		c.Next()
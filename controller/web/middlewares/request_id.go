@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
+
+// NewRequestIDAssigner identifies each request by the X-Request-ID header of the client, or a generated ID if it is absent.
+// The ID is echoed in the response and added to the logger in the request context.
+func NewRequestIDAssigner(idGenerator port.IDGenerator) handlers.Handler {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			id, err := idGenerator.Generate(ctx)
+			if err != nil {
+				// Recovery is not installed yet, so the problem is written here
+				util.FromContext(ctx).Error(err, "failed to generate request id")
+				abortWithProblem(c, newProblem(c, http.StatusInternalServerError, ProblemCodeInternal, ""))
+				return
+			}
+			requestID = id.String()
+		}
+		c.Header(RequestIDHeader, requestID)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("http.request.id", requestID))
+
+		ctx = util.NewContextWithLogger(ctx, util.FromContext(ctx).WithValues("requestID", requestID))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// validRequestID accepts IDs of visible ASCII characters, so that they are safe to log and echo.
+func validRequestID(v string) bool {
+	if len(v) == 0 || len(v) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(v); i++ {
+		if v[i] < '!' || v[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-ws-sample/adapter/id"
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// valuesLogger records the values added to it and the errors logged by it.
+type valuesLogger struct {
+	util.Logger
+	values []interface{}
+	errs   *[]error
+}
+
+func newValuesLogger() *valuesLogger {
+	return &valuesLogger{
+		Logger: util.GLogger(),
+		errs:   new([]error),
+	}
+}
+
+func (l *valuesLogger) WithValues(keysAndValues ...interface{}) util.Logger {
+	return &valuesLogger{
+		Logger: l.Logger,
+		values: append(append([]interface{}{}, l.values...), keysAndValues...),
+		errs:   l.errs,
+	}
+}
+
+func (l *valuesLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	*l.errs = append(*l.errs, err)
+}
+
+func TestNewRequestIDAssigner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name          string
+		requestID     string
+		wantGenerated bool
+	}{
+		{
+			name:      "accept the request id of the client",
+			requestID: "client-request-1",
+		},
+		{
+			name:      "accept a request id of 128 characters",
+			requestID: strings.Repeat("a", 128),
+		},
+		{
+			name:          "generate a request id when it is absent",
+			wantGenerated: true,
+		},
+		{
+			name:          "replace a request id longer than 128 characters",
+			requestID:     strings.Repeat("a", 129),
+			wantGenerated: true,
+		},
+		{
+			name:          "replace a request id with invisible characters",
+			requestID:     "client request\t1",
+			wantGenerated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := newValuesLogger()
+			var handledID string
+			var handledLogger util.Logger
+			e := gin.New()
+			e.Use(func(c *gin.Context) {
+				c.Request = c.Request.WithContext(util.NewContextWithLogger(c.Request.Context(), logger))
+			})
+			e.Use(gin.HandlerFunc(NewRequestIDAssigner(id.NewULIDGenerator())))
+			e.GET("/", func(c *gin.Context) {
+				handledID = c.Writer.Header().Get(RequestIDHeader)
+				handledLogger = util.FromContext(c.Request.Context())
+				c.Status(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if len(tt.requestID) > 0 {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+			got := rec.Header().Get(RequestIDHeader)
+			if tt.wantGenerated {
+				_, err := ulid.ParseStrict(got)
+				assert.NoError(t, err, "request id %q is not a ULID", got)
+				assert.NotEqual(t, tt.requestID, got)
+			} else {
+				assert.Equal(t, tt.requestID, got)
+			}
+			assert.Equal(t, got, handledID)
+			require.IsType(t, &valuesLogger{}, handledLogger)
+			assert.Equal(t, []interface{}{"requestID", got}, handledLogger.(*valuesLogger).values)
+		})
+	}
+}
+
+func TestNewRequestIDAssigner_GenerateError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := newValuesLogger()
+	idGenerator := mocks.NewIDGenerator(t)
+	idGenerator.On("Generate", mock.Anything).Return(entity.ID(""), assert.AnError)
+
+	var handled bool
+	e := gin.New()
+	e.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(util.NewContextWithLogger(c.Request.Context(), logger))
+	})
+	e.Use(gin.HandlerFunc(NewRequestIDAssigner(idGenerator)), gin.HandlerFunc(Recovery()))
+	e.GET("/", func(c *gin.Context) {
+		handled = true
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.False(t, handled)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, MIMEProblemJSON, rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Header().Get(RequestIDHeader))
+	var got Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, ProblemCodeInternal, got.Code)
+	assert.Equal(t, []error{assert.AnError}, *logger.errs)
+}
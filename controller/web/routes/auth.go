@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+)
+
+func NewAuthRoutes(
+	authenticate handlers.Handler,
+	tokenIssue *handlers.IssueTokenHandler,
+) Routes {
+	return Routes{
+		{
+			method:     http.MethodPost,
+			path:       "/auth/token",
+			handlers:   handlers.Handlers{authenticate, tokenIssue.Handle},
+			credential: true,
+		},
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+)
+
+func NewMetricsRoutes(
+	metricsGet *handlers.MetricsGetHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodGet,
+			path:     "/metrics",
+			handlers: handlers.Handlers{metricsGet.Handle},
+		},
+	}
+}
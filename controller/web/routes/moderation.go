@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+)
+
+func NewModerationRoutes(
+	authenticate handlers.Handler,
+	kicksCreate *handlers.KickMemberHandler,
+	bansCreate *handlers.SanctionMemberHandler,
+	bansDelete *handlers.LiftSanctionHandler,
+	mutesCreate *handlers.SanctionMemberHandler,
+	mutesDelete *handlers.LiftSanctionHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodPost,
+			path:     "/rooms/:room_id/kicks",
+			handlers: handlers.Handlers{authenticate, kicksCreate.Handle},
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/rooms/:room_id/bans",
+			handlers: handlers.Handlers{authenticate, bansCreate.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/rooms/:room_id/bans/:user_id",
+			handlers: handlers.Handlers{authenticate, bansDelete.Handle},
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/rooms/:room_id/mutes",
+			handlers: handlers.Handlers{authenticate, mutesCreate.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/rooms/:room_id/mutes/:user_id",
+			handlers: handlers.Handlers{authenticate, mutesDelete.Handle},
+		},
+	}
+}
@@ -7,31 +7,74 @@ import (
 )
 
 func NewRoomsRoutes(
+	authenticate handlers.Handler,
 	roomsList *handlers.ListRoomsHandler,
 	roomsGet *handlers.GetRoomHandler,
 	roomsCreate *handlers.CreateRoomHandler,
+	roomsUpdate *handlers.UpdateRoomHandler,
 	roomsDelete *handlers.DeleteRoomHandler,
+	roomMessagesStream *handlers.StreamRoomMessagesHandler,
+	roomMessagesList *handlers.ListMessagesHandler,
+	roomMessagesRemove *handlers.RemoveMessageHandler,
+	roomMembersList *handlers.ListRoomMembersHandler,
+	roomMembersAdd *handlers.AddRoomMemberHandler,
+	roomMembersRemove *handlers.RemoveRoomMemberHandler,
 ) Routes {
 	return Routes{
 		{
 			method:   http.MethodGet,
 			path:     "/rooms",
-			handlers: handlers.Handlers{roomsList.Handle},
+			handlers: handlers.Handlers{authenticate, roomsList.Handle},
 		},
 		{
 			method:   http.MethodPost,
 			path:     "/rooms",
-			handlers: handlers.Handlers{roomsCreate.Handle},
+			handlers: handlers.Handlers{authenticate, roomsCreate.Handle},
 		},
 		{
 			method:   http.MethodGet,
 			path:     "/rooms/:room_id",
-			handlers: handlers.Handlers{roomsGet.Handle},
+			handlers: handlers.Handlers{authenticate, roomsGet.Handle},
+		},
+		{
+			method:   http.MethodPatch,
+			path:     "/rooms/:room_id",
+			handlers: handlers.Handlers{authenticate, roomsUpdate.Handle},
 		},
 		{
 			method:   http.MethodDelete,
 			path:     "/rooms/:room_id",
-			handlers: handlers.Handlers{roomsDelete.Handle},
+			handlers: handlers.Handlers{authenticate, roomsDelete.Handle},
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/rooms/:room_id/messages",
+			handlers: handlers.Handlers{authenticate, roomMessagesStream.Handle},
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/rooms/:room_id/messages/history",
+			handlers: handlers.Handlers{authenticate, roomMessagesList.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/rooms/:room_id/messages/:message_id",
+			handlers: handlers.Handlers{authenticate, roomMessagesRemove.Handle},
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/rooms/:room_id/members",
+			handlers: handlers.Handlers{authenticate, roomMembersList.Handle},
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/rooms/:room_id/members",
+			handlers: handlers.Handlers{authenticate, roomMembersAdd.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/rooms/:room_id/members/:user_id",
+			handlers: handlers.Handlers{authenticate, roomMembersRemove.Handle},
 		},
 	}
 }
@@ -8,6 +8,8 @@ type Route struct {
 	method   string
 	path     string
 	handlers handlers.Handlers
+	// credential is set on the routes accepting a password, which have to be guarded before the verification.
+	credential bool
 }
 
 func (r *Route) Method() string {
@@ -22,4 +24,41 @@ func (r *Route) Handlers() handlers.Handlers {
 	return r.handlers
 }
 
+// Credential reports whether the authentication of the route accepts a password.
+func (r *Route) Credential() bool {
+	return r.credential
+}
+
 type Routes []*Route
+
+// WithCredential marks the routes as accepting a password, for the ones authenticated by Basic auth too.
+func (rs Routes) WithCredential() Routes {
+	for _, r := range rs {
+		r.credential = true
+	}
+	return rs
+}
+
+// Find returns the route of the method and path template, or nil.
+func (rs Routes) Find(method string, path string) *Route {
+	for _, r := range rs {
+		if r.method == method && r.path == path {
+			return r
+		}
+	}
+	return nil
+}
+
+// Use inserts middleware right before the handler of the route, after the authentication.
+// The middleware can rely on the authenticated user, but it is skipped for the requests failing the authentication.
+func (r *Route) Use(middleware ...handlers.Handler) {
+	last := len(r.handlers) - 1
+	hs := append(handlers.Handlers{}, r.handlers[:last]...)
+	hs = append(hs, middleware...)
+	r.handlers = append(hs, r.handlers[last])
+}
+
+// UseFirst inserts middleware before all the handlers of the route, so it runs before the authentication.
+func (r *Route) UseFirst(middleware ...handlers.Handler) {
+	r.handlers = append(append(handlers.Handlers{}, middleware...), r.handlers...)
+}
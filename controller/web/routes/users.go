@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
+)
+
+func NewUsersRoutes(
+	usersCreate *handlers.CreateUserHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodPost,
+			path:     "/users",
+			handlers: handlers.Handlers{usersCreate.Handle},
+		},
+	}
+}
@@ -1,14 +1,27 @@
 package web
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"sync"
+
 	"github.com/gin-gonic/gin"
 	"github.com/mkaiho/go-ws-sample/controller/web/handlers"
 	"github.com/mkaiho/go-ws-sample/controller/web/middlewares"
 	"github.com/mkaiho/go-ws-sample/controller/web/routes"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
 )
 
+// ShutdownHook releases a resource while the server shuts down.
+type ShutdownHook func(ctx context.Context) error
+
 type Server struct {
-	e *gin.Engine
+	e          *gin.Engine
+	srv        *http.Server
+	mux        sync.Mutex
+	onShutdown []ShutdownHook
 }
 
 func (s *Server) Use(middleware ...handlers.Handler) {
@@ -25,15 +38,67 @@ func (s *Server) Handle(httpMethod string, relativePath string, h ...handlers.Ha
 	s.e.Handle(httpMethod, relativePath, hs...)
 }
 
-func (s *Server) Run(addr ...string) error {
-	return s.e.Run(addr...)
+// Run serves on addr until Shutdown is called.
+func (s *Server) Run(addr string) error {
+	s.srv.Addr = addr
+	if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// RunTLS serves HTTPS on addr until Shutdown is called, HTTP/2 is negotiated with the clients supporting it.
+// The certificates are taken from conf.
+func (s *Server) RunTLS(addr string, conf *tls.Config) error {
+	s.srv.Addr = addr
+	s.srv.TLSConfig = conf
+	if err := s.srv.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// OnShutdown registers hooks called by Shutdown in the registered order.
+func (s *Server) OnShutdown(hooks ...ShutdownHook) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.onShutdown = append(s.onShutdown, hooks...)
+}
+
+// Shutdown stops accepting requests, waits for the active ones and then calls the shutdown hooks.
+// WebSocket connections are hijacked from the http.Server, so a hook has to close them.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mux.Lock()
+	hooks := s.onShutdown
+	s.mux.Unlock()
+
+	var errs []error
+	if err := s.srv.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
-func NewGinServer(r ...*routes.Route) *Server {
+func NewGinServer(idGenerator port.IDGenerator, metrics port.Metrics, r ...*routes.Route) *Server {
+	e := gin.New()
 	server := &Server{
-		e: gin.New(),
+		e: e,
+		srv: &http.Server{
+			Handler: e.Handler(),
+		},
 	}
-	server.Use(middlewares.NewGinLogger(), middlewares.Recovery())
+	server.Use(
+		middlewares.NewTracer(),
+		middlewares.NewRequestIDAssigner(idGenerator),
+		middlewares.NewGinLogger(),
+		middlewares.NewMetricsRecorder(metrics),
+		middlewares.Recovery(),
+	)
 	for _, route := range r {
 		server.Handle(route.Method(), route.Path(), route.Handlers()...)
 	}
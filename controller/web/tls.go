@@ -0,0 +1,119 @@
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+// CertReloader serves a certificate key pair and reloads it when the files change.
+// Only new handshakes use a reloaded certificate, established connections are kept.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	mux     sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func NewCertReloader(certFile string, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.cert, nil
+}
+
+// Reload loads the key pair if either file was modified since the last load, and reports whether it did.
+func (r *CertReloader) Reload() (bool, error) {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return false, err
+	}
+	r.mux.RLock()
+	unchanged := r.cert != nil && modTime.Equal(r.modTime)
+	r.mux.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load certificate: %w", err)
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.cert = &cert
+	r.modTime = modTime
+	return true, nil
+}
+
+// Watch reloads the key pair every interval until ctx is done.
+// A pair failing to load, e.g. while only one of the files is replaced, keeps the previous one in use.
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration) {
+	logger := util.FromContext(ctx).WithValues("certFile", r.certFile, "keyFile", r.keyFile)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		reloaded, err := r.Reload()
+		if err != nil {
+			logger.Error(err, "failed to reload certificate")
+			continue
+		}
+		if reloaded {
+			logger.Info("reloaded certificate")
+		}
+	}
+}
+
+func (r *CertReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to stat certificate: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// NewTLSConfig serves the certificate of the reloader.
+// Clients must present a certificate signed by a CA in clientCAFile unless it is empty.
+func NewTLSConfig(certs *CertReloader, clientCAFile string) (*tls.Config, error) {
+	conf := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certs.GetCertificate,
+	}
+	if len(clientCAFile) > 0 {
+		pool, err := util.LoadCertPool(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.ClientCAs = pool
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return conf, nil
+}
@@ -0,0 +1,78 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKeyPair(t *testing.T, certFile string, keyFile string, commonName string, modTime time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func TestCertReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	loadedAt := time.Now().Add(-time.Minute)
+	writeKeyPair(t, certFile, keyFile, "first", loadedAt)
+
+	reloader, err := NewCertReloader(certFile, keyFile)
+	require.NoError(t, err)
+	commonName := func() string {
+		cert, err := reloader.GetCertificate(nil)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		return leaf.Subject.CommonName
+	}
+	assert.Equal(t, "first", commonName())
+
+	t.Run("keep certificate while files are unchanged", func(t *testing.T) {
+		reloaded, err := reloader.Reload()
+		assert.NoError(t, err)
+		assert.False(t, reloaded)
+		assert.Equal(t, "first", commonName())
+	})
+	t.Run("load certificate when files are replaced", func(t *testing.T) {
+		writeKeyPair(t, certFile, keyFile, "second", loadedAt.Add(time.Second))
+		reloaded, err := reloader.Reload()
+		assert.NoError(t, err)
+		assert.True(t, reloaded)
+		assert.Equal(t, "second", commonName())
+	})
+	t.Run("keep certificate when replaced files are invalid", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(keyFile, []byte("broken"), 0o600))
+		_, err := reloader.Reload()
+		assert.Error(t, err)
+		assert.Equal(t, "second", commonName())
+	})
+}
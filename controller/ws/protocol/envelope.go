@@ -0,0 +1,127 @@
+// Package protocol defines the frames exchanged over the room message stream.
+// Every frame is a JSON envelope whose payload depends on its type.
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	validatorlib "github.com/go-playground/validator/v10"
+)
+
+// Version is the envelope version this package speaks.
+const Version = 1
+
+var validator = newValidator()
+
+// newValidator reports fields by their JSON names, as clients know them.
+func newValidator() *validatorlib.Validate {
+	v := validatorlib.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+type Type string
+
+const (
+	// TypeMessagePost is sent by clients to post a message to the room.
+	TypeMessagePost Type = "message.post"
+	// TypeMessageCreated is sent by the server when a message is posted to the room.
+	TypeMessageCreated Type = "message.created"
+	// TypeRoomUpdated is sent by the server when the name or description of the room is changed.
+	TypeRoomUpdated Type = "room.updated"
+	// TypeRoomDeleted is sent by the server when the room is deleted, the connection is closed after it.
+	TypeRoomDeleted Type = "room.deleted"
+	// TypeError is sent by the server when a frame from the client is rejected.
+	TypeError Type = "error"
+	// TypeAck is sent by the server when a frame from the client is processed.
+	TypeAck Type = "ack"
+	// TypePing is sent by clients to check the connection, the server replies with an ack.
+	TypePing Type = "ping"
+	// TypeMemberKick is sent by moderators to close the connections of a member to the room.
+	TypeMemberKick Type = "member.kick"
+	// TypeMemberBan is sent by moderators to keep a user from entering the room, the user is kicked as well.
+	TypeMemberBan Type = "member.ban"
+	// TypeMemberMute is sent by moderators to keep a user from posting messages to the room.
+	TypeMemberMute Type = "member.mute"
+)
+
+type Envelope struct {
+	V    int  `json:"v"`
+	Type Type `json:"type"`
+	// ID is chosen by the client and echoed in the ack or error replying to the frame.
+	ID      string          `json:"id,omitempty" validate:"max=64"`
+	RoomID  string          `json:"room_id,omitempty" validate:"max=26"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Decode parses and validates a frame sent by a client.
+// The returned error is always an *Error.
+func Decode(data []byte) (*Envelope, error) {
+	var e Envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, NewError(ErrorCodeInvalidFrame, "frame is not a valid envelope")
+	}
+	if e.V != Version {
+		return nil, NewError(ErrorCodeUnsupportedVersion, fmt.Sprintf("version %d is not supported", e.V)).WithID(e.ID)
+	}
+	switch e.Type {
+	case TypeMessagePost, TypePing, TypeMemberKick, TypeMemberBan, TypeMemberMute:
+	default:
+		return nil, NewError(ErrorCodeUnsupportedType, fmt.Sprintf("type %q is not supported", e.Type)).WithID(e.ID)
+	}
+	if err := validator.Struct(&e); err != nil {
+		return nil, NewError(ErrorCodeInvalidFrame, validationMessage(err)).WithID(e.ID)
+	}
+	return &e, nil
+}
+
+// DecodePayload parses and validates the payload of e into v.
+// The returned error is always an *Error.
+func DecodePayload(e *Envelope, v any) error {
+	if len(e.Payload) == 0 {
+		return NewError(ErrorCodeInvalidPayload, "payload is required").WithID(e.ID)
+	}
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return NewError(ErrorCodeInvalidPayload, "payload is malformed").WithID(e.ID)
+	}
+	if err := validator.Struct(v); err != nil {
+		return NewError(ErrorCodeInvalidPayload, validationMessage(err)).WithID(e.ID)
+	}
+	return nil
+}
+
+// Encode builds a frame of the current version.
+func Encode(typ Type, id string, roomID string, payload any) ([]byte, error) {
+	e := Envelope{
+		V:      Version,
+		Type:   typ,
+		ID:     id,
+		RoomID: roomID,
+	}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode payload: %w", err)
+		}
+		e.Payload = raw
+	}
+	return json.Marshal(&e)
+}
+
+func validationMessage(err error) string {
+	var vErrs validatorlib.ValidationErrors
+	if errors.As(err, &vErrs) && len(vErrs) > 0 {
+		return fmt.Sprintf("%s does not satisfy %s", vErrs[0].Field(), vErrs[0].Tag())
+	}
+	return err.Error()
+}
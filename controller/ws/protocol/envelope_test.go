@@ -0,0 +1,169 @@
+package protocol
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	type args struct {
+		data string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     *Envelope
+		wantCode ErrorCode
+		wantID   string
+	}{
+		{
+			name: "return envelope of message.post",
+			args: args{
+				data: `{"v":1,"type":"message.post","id":"1","payload":{"body":"hello"}}`,
+			},
+			want: &Envelope{
+				V:       1,
+				Type:    TypeMessagePost,
+				ID:      "1",
+				Payload: json.RawMessage(`{"body":"hello"}`),
+			},
+		},
+		{
+			name: "return invalid_frame when frame is not json",
+			args: args{
+				data: `hello`,
+			},
+			wantCode: ErrorCodeInvalidFrame,
+		},
+		{
+			name: "return unsupported_version when version differs",
+			args: args{
+				data: `{"v":2,"type":"ping","id":"1"}`,
+			},
+			wantCode: ErrorCodeUnsupportedVersion,
+			wantID:   "1",
+		},
+		{
+			name: "return unsupported_type when type is sent only by server",
+			args: args{
+				data: `{"v":1,"type":"message.created","id":"1"}`,
+			},
+			wantCode: ErrorCodeUnsupportedType,
+			wantID:   "1",
+		},
+		{
+			name: "return invalid_frame when id is too long",
+			args: args{
+				data: `{"v":1,"type":"ping","id":"` + strings.Repeat("a", 65) + `"}`,
+			},
+			wantCode: ErrorCodeInvalidFrame,
+			wantID:   strings.Repeat("a", 65),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode([]byte(tt.args.data))
+			if len(tt.wantCode) > 0 {
+				var pErr *Error
+				if assert.ErrorAs(t, err, &pErr) {
+					assert.Equal(t, tt.wantCode, pErr.Code)
+					assert.Equal(t, tt.wantID, pErr.ID())
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecodePayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		want     *MessagePostPayload
+		wantCode ErrorCode
+	}{
+		{
+			name:    "return payload",
+			payload: `{"body":"hello","idempotency_key":"k1"}`,
+			want:    &MessagePostPayload{Body: "hello", IdempotencyKey: "k1"},
+		},
+		{
+			name:     "return invalid_payload when payload is missing",
+			wantCode: ErrorCodeInvalidPayload,
+		},
+		{
+			name:     "return invalid_payload when body is empty",
+			payload:  `{"body":"","idempotency_key":"k1"}`,
+			wantCode: ErrorCodeInvalidPayload,
+		},
+		{
+			name:     "return invalid_payload when idempotency key is missing",
+			payload:  `{"body":"hello"}`,
+			wantCode: ErrorCodeInvalidPayload,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Envelope{V: Version, Type: TypeMessagePost, ID: "1", Payload: json.RawMessage(tt.payload)}
+			var got MessagePostPayload
+			err := DecodePayload(e, &got)
+			if len(tt.wantCode) > 0 {
+				var pErr *Error
+				if assert.ErrorAs(t, err, &pErr) {
+					assert.Equal(t, tt.wantCode, pErr.Code)
+					assert.Equal(t, "1", pErr.ID())
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, &got)
+		})
+	}
+}
+
+func TestDecodePayload_MemberSanction(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		wantCode ErrorCode
+	}{
+		{
+			name:    "return payload without expiry",
+			payload: `{"user_id":"01HNZ0000000000000000000AA"}`,
+		},
+		{
+			name:    "return payload with future expiry",
+			payload: `{"user_id":"01HNZ0000000000000000000AA","expires_at":"2999-01-01T00:00:00Z"}`,
+		},
+		{
+			name:     "return invalid_payload when expiry has passed",
+			payload:  `{"user_id":"01HNZ0000000000000000000AA","expires_at":"2000-01-01T00:00:00Z"}`,
+			wantCode: ErrorCodeInvalidPayload,
+		},
+		{
+			name:     "return invalid_payload when user id is missing",
+			payload:  `{}`,
+			wantCode: ErrorCodeInvalidPayload,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Envelope{V: Version, Type: TypeMemberBan, ID: "1", Payload: json.RawMessage(tt.payload)}
+			var got MemberSanctionPayload
+			err := DecodePayload(e, &got)
+			if len(tt.wantCode) > 0 {
+				var pErr *Error
+				if assert.ErrorAs(t, err, &pErr) {
+					assert.Equal(t, tt.wantCode, pErr.Code)
+				}
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package protocol
+
+// ErrorCode identifies the reason of an error frame.
+// Codes are part of the protocol, clients may branch on them, so they must never change.
+type ErrorCode string
+
+const (
+	// ErrorCodeInvalidFrame is sent for frames that are not a valid envelope.
+	ErrorCodeInvalidFrame ErrorCode = "invalid_frame"
+	// ErrorCodeUnsupportedVersion is sent for envelopes of another version.
+	ErrorCodeUnsupportedVersion ErrorCode = "unsupported_version"
+	// ErrorCodeUnsupportedType is sent for types clients must not send.
+	ErrorCodeUnsupportedType ErrorCode = "unsupported_type"
+	// ErrorCodeInvalidPayload is sent for payloads that are malformed or fail validation.
+	ErrorCodeInvalidPayload ErrorCode = "invalid_payload"
+	// ErrorCodeRoomMismatch is sent for envelopes addressed to another room than the stream.
+	ErrorCodeRoomMismatch ErrorCode = "room_mismatch"
+	// ErrorCodeRateLimited is sent for frames over the rate limit of the connection, they are not processed.
+	ErrorCodeRateLimited ErrorCode = "rate_limited"
+	// ErrorCodeForbidden is sent for commands the role of the user in the room does not allow.
+	ErrorCodeForbidden ErrorCode = "forbidden"
+	// ErrorCodeNotFound is sent for commands targeting a user who does not exist or is not a member.
+	ErrorCodeNotFound ErrorCode = "not_found"
+	// ErrorCodeMuted is sent for message.post while the user is muted in the room.
+	ErrorCodeMuted ErrorCode = "muted"
+	// ErrorCodeInternal is sent when the server fails to process a valid frame.
+	ErrorCodeInternal ErrorCode = "internal_error"
+)
+
+// Error is the payload of an error frame.
+type Error struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	// id is the envelope ID the error replies to.
+	id string
+}
+
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return string(e.Code) + ": " + e.Message
+}
+
+// WithID returns a copy of e replying to the envelope id.
+func (e *Error) WithID(id string) *Error {
+	c := *e
+	c.id = id
+	return &c
+}
+
+// ID returns the envelope ID the error replies to.
+func (e *Error) ID() string {
+	return e.id
+}
@@ -0,0 +1,79 @@
+package protocol
+
+import (
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+)
+
+type MessagePostPayload struct {
+	Body string `json:"body" validate:"required,max=4096"`
+	// IdempotencyKey is generated by the client for each message and reused when the post is retried.
+	IdempotencyKey string `json:"idempotency_key" validate:"required,max=64"`
+}
+
+// AckPayload is the payload of an ack replying to message.post.
+type AckPayload struct {
+	MessageID string `json:"message_id"`
+}
+
+// MemberKickPayload is the payload of member.kick.
+type MemberKickPayload struct {
+	UserID string `json:"user_id" validate:"required,max=26"`
+}
+
+// MemberSanctionPayload is the payload of member.ban and member.mute.
+type MemberSanctionPayload struct {
+	UserID string `json:"user_id" validate:"required,max=26"`
+	// ExpiresAt is when the sanction is lifted by itself, it lasts until it is lifted through the API when omitted.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" validate:"omitempty,gt"`
+}
+
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type MessageCreatedPayload struct {
+	ID             string     `json:"id"`
+	RoomID         string     `json:"room_id"`
+	Body           string     `json:"body"`
+	PostedDatetime *time.Time `json:"posted_datetime,omitempty"`
+	PostedBy       *User      `json:"posted_by,omitempty"`
+}
+
+func NewMessageCreatedPayload(message *entity.PostMessage) *MessageCreatedPayload {
+	payload := &MessageCreatedPayload{
+		ID:             message.ID.String(),
+		RoomID:         message.RoomID.String(),
+		Body:           message.Body,
+		PostedDatetime: message.PostedDatetime,
+	}
+	if message.PostedBy != nil {
+		payload.PostedBy = &User{
+			ID:   message.PostedBy.ID.String(),
+			Name: message.PostedBy.Name,
+		}
+	}
+	return payload
+}
+
+type RoomUpdatedPayload struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Version     int     `json:"version"`
+}
+
+func NewRoomUpdatedPayload(room *entity.Room) *RoomUpdatedPayload {
+	return &RoomUpdatedPayload{
+		ID:          room.ID.String(),
+		Name:        room.Name,
+		Description: room.Description,
+		Version:     room.Version,
+	}
+}
+
+type RoomDeletedPayload struct {
+	ID string `json:"id"`
+}
@@ -0,0 +1,48 @@
+package entity
+
+// Role is what a member is allowed to do in a room.
+type Role string
+
+const (
+	// RoleOwner is given to the creator of a room, and may do anything in it.
+	RoleOwner Role = "owner"
+	// RoleModerator may manage members and remove messages.
+	RoleModerator Role = "moderator"
+	// RoleMember may read and post messages.
+	RoleMember Role = "member"
+)
+
+func (r Role) String() string {
+	return string(r)
+}
+
+// Rank orders roles by privilege, a role outranks every role of a lower rank.
+func (r Role) Rank() int {
+	switch r {
+	case RoleOwner:
+		return 3
+	case RoleModerator:
+		return 2
+	case RoleMember:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type Member struct {
+	User *User
+	Role Role
+}
+
+type Members []*Member
+
+// Find returns the member of the user, or nil when the user is not a member.
+func (ms Members) Find(userID ID) *Member {
+	for _, m := range ms {
+		if m.User.ID == userID {
+			return m
+		}
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import "time"
 
 type PostMessage struct {
 	ID             ID
+	RoomID         ID
+	Body           string
 	PostedDatetime *time.Time
 	PostedBy       *User
 }
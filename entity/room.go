@@ -4,8 +4,10 @@ type Room struct {
 	ID          ID
 	Name        string
 	Description *string
-	Messages    PostMessages
-	Users       Users
+	// Version starts at 1 and is incremented by every update of the room.
+	Version  int
+	Messages PostMessages
+	Members  Members
 }
 
 type Rooms []*Room
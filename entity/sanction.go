@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+type SanctionType string
+
+const (
+	// SanctionTypeBan keeps a user from entering the room.
+	SanctionTypeBan SanctionType = "ban"
+	// SanctionTypeMute keeps a user from posting messages to the room, they can still read them.
+	SanctionTypeMute SanctionType = "mute"
+)
+
+func (t SanctionType) String() string {
+	return string(t)
+}
+
+// Sanction restricts a user in a room until it expires, or until it is lifted when ExpiresAt is nil.
+type Sanction struct {
+	RoomID    ID
+	UserID    ID
+	Type      SanctionType
+	ExpiresAt *time.Time
+}
+
+// Active reports whether the sanction is still in effect at now.
+func (s *Sanction) Active(now time.Time) bool {
+	return s.ExpiresAt == nil || now.Before(*s.ExpiresAt)
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-ws-sample/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MessageBus is an autogenerated mock type for the MessageBus type
+type MessageBus struct {
+	mock.Mock
+}
+
+// Publish provides a mock function with given fields: ctx, input
+func (_m *MessageBus) Publish(ctx context.Context, input *port.PublishMessageBusInput) (*port.PublishMessageBusOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 *port.PublishMessageBusOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.PublishMessageBusInput) (*port.PublishMessageBusOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.PublishMessageBusInput) *port.PublishMessageBusOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.PublishMessageBusOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.PublishMessageBusInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Subscribe provides a mock function with given fields: ctx, input
+func (_m *MessageBus) Subscribe(ctx context.Context, input *port.SubscribeMessageBusInput) (*port.SubscribeMessageBusOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 *port.SubscribeMessageBusOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.SubscribeMessageBusInput) (*port.SubscribeMessageBusOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.SubscribeMessageBusInput) *port.SubscribeMessageBusOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.SubscribeMessageBusOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.SubscribeMessageBusInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMessageBus creates a new instance of MessageBus. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMessageBus(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MessageBus {
+	mock := &MessageBus{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,33 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MessageBusHandler is an autogenerated mock type for the MessageBusHandler type
+type MessageBusHandler struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: ctx, payload
+func (_m *MessageBusHandler) Execute(ctx context.Context, payload []byte) {
+	_m.Called(ctx, payload)
+}
+
+// NewMessageBusHandler creates a new instance of MessageBusHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMessageBusHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MessageBusHandler {
+	mock := &MessageBusHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
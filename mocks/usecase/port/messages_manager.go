@@ -0,0 +1,119 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-ws-sample/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MessagesManager is an autogenerated mock type for the MessagesManager type
+type MessagesManager struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, input
+func (_m *MessagesManager) Create(ctx context.Context, input *port.CreateMessageInput) (*port.CreateMessageOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *port.CreateMessageOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.CreateMessageInput) (*port.CreateMessageOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.CreateMessageInput) *port.CreateMessageOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.CreateMessageOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.CreateMessageInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, input
+func (_m *MessagesManager) Delete(ctx context.Context, input *port.DeleteMessagesInput) (*port.DeleteMessagesOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *port.DeleteMessagesOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.DeleteMessagesInput) (*port.DeleteMessagesOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.DeleteMessagesInput) *port.DeleteMessagesOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.DeleteMessagesOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.DeleteMessagesInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Find provides a mock function with given fields: ctx, input
+func (_m *MessagesManager) Find(ctx context.Context, input *port.FindMessagesInput) (*port.FindMessagesOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 *port.FindMessagesOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.FindMessagesInput) (*port.FindMessagesOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.FindMessagesInput) *port.FindMessagesOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.FindMessagesOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.FindMessagesInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMessagesManager creates a new instance of MessagesManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMessagesManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MessagesManager {
+	mock := &MessagesManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
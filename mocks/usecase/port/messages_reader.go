@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-ws-sample/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MessagesReader is an autogenerated mock type for the MessagesReader type
+type MessagesReader struct {
+	mock.Mock
+}
+
+// Find provides a mock function with given fields: ctx, input
+func (_m *MessagesReader) Find(ctx context.Context, input *port.FindMessagesInput) (*port.FindMessagesOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 *port.FindMessagesOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.FindMessagesInput) (*port.FindMessagesOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.FindMessagesInput) *port.FindMessagesOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.FindMessagesOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.FindMessagesInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMessagesReader creates a new instance of MessagesReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMessagesReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MessagesReader {
+	mock := &MessagesReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,56 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-ws-sample/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Metrics is an autogenerated mock type for the Metrics type
+type Metrics struct {
+	mock.Mock
+}
+
+// AddConnections provides a mock function with given fields: ctx, roomID, delta
+func (_m *Metrics) AddConnections(ctx context.Context, roomID entity.ID, delta int) {
+	_m.Called(ctx, roomID, delta)
+}
+
+// IncMessagesReceived provides a mock function with given fields: ctx
+func (_m *Metrics) IncMessagesReceived(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// IncMessagesSent provides a mock function with given fields: ctx
+func (_m *Metrics) IncMessagesSent(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// IncSlowConsumersDropped provides a mock function with given fields: ctx
+func (_m *Metrics) IncSlowConsumersDropped(ctx context.Context) {
+	_m.Called(ctx)
+}
+
+// ObserveHTTPRequest provides a mock function with given fields: ctx, method, route, status, duration
+func (_m *Metrics) ObserveHTTPRequest(ctx context.Context, method string, route string, status int, duration time.Duration) {
+	_m.Called(ctx, method, route, status, duration)
+}
+
+// NewMetrics creates a new instance of Metrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMetrics(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Metrics {
+	mock := &Metrics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
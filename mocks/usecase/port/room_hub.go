@@ -0,0 +1,119 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-ws-sample/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RoomHub is an autogenerated mock type for the RoomHub type
+type RoomHub struct {
+	mock.Mock
+}
+
+// Broadcast provides a mock function with given fields: ctx, input
+func (_m *RoomHub) Broadcast(ctx context.Context, input *port.BroadcastRoomHubInput) (*port.BroadcastRoomHubOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Broadcast")
+	}
+
+	var r0 *port.BroadcastRoomHubOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.BroadcastRoomHubInput) (*port.BroadcastRoomHubOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.BroadcastRoomHubInput) *port.BroadcastRoomHubOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.BroadcastRoomHubOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.BroadcastRoomHubInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Join provides a mock function with given fields: ctx, input
+func (_m *RoomHub) Join(ctx context.Context, input *port.JoinRoomHubInput) (*port.JoinRoomHubOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Join")
+	}
+
+	var r0 *port.JoinRoomHubOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.JoinRoomHubInput) (*port.JoinRoomHubOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.JoinRoomHubInput) *port.JoinRoomHubOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.JoinRoomHubOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.JoinRoomHubInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Leave provides a mock function with given fields: ctx, input
+func (_m *RoomHub) Leave(ctx context.Context, input *port.LeaveRoomHubInput) (*port.LeaveRoomHubOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Leave")
+	}
+
+	var r0 *port.LeaveRoomHubOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.LeaveRoomHubInput) (*port.LeaveRoomHubOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.LeaveRoomHubInput) *port.LeaveRoomHubOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.LeaveRoomHubOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.LeaveRoomHubInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRoomHub creates a new instance of RoomHub. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoomHub(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoomHub {
+	mock := &RoomHub{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,65 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-ws-sample/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RoomHubConn is an autogenerated mock type for the RoomHubConn type
+type RoomHubConn struct {
+	mock.Mock
+}
+
+// Close provides a mock function with given fields: ctx, reason
+func (_m *RoomHubConn) Close(ctx context.Context, reason port.CloseReason) error {
+	ret := _m.Called(ctx, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.CloseReason) error); ok {
+		r0 = rf(ctx, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Send provides a mock function with given fields: ctx, event
+func (_m *RoomHubConn) Send(ctx context.Context, event *port.RoomEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.RoomEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRoomHubConn creates a new instance of RoomHubConn. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoomHubConn(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoomHubConn {
+	mock := &RoomHubConn{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
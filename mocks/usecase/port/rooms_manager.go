@@ -1,4 +1,4 @@
-// Code generated by mockery v2.40.1. DO NOT EDIT.
+// Code generated by mockery v2.53.3. DO NOT EDIT.
 
 package mocks
 
@@ -14,6 +14,36 @@ type RoomsManager struct {
 	mock.Mock
 }
 
+// AddMember provides a mock function with given fields: ctx, input
+func (_m *RoomsManager) AddMember(ctx context.Context, input *port.AddRoomMemberInput) (*port.AddRoomMemberOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddMember")
+	}
+
+	var r0 *port.AddRoomMemberOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.AddRoomMemberInput) (*port.AddRoomMemberOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.AddRoomMemberInput) *port.AddRoomMemberOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.AddRoomMemberOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.AddRoomMemberInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, input
 func (_m *RoomsManager) Create(ctx context.Context, input *port.CreateRoomInput) (*port.CreateRoomOutput, error) {
 	ret := _m.Called(ctx, input)
@@ -44,6 +74,36 @@ func (_m *RoomsManager) Create(ctx context.Context, input *port.CreateRoomInput)
 	return r0, r1
 }
 
+// Delete provides a mock function with given fields: ctx, input
+func (_m *RoomsManager) Delete(ctx context.Context, input *port.DeleteRoomInput) (*port.DeleteRoomOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *port.DeleteRoomOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.DeleteRoomInput) (*port.DeleteRoomOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.DeleteRoomInput) *port.DeleteRoomOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.DeleteRoomOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.DeleteRoomInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Find provides a mock function with given fields: ctx, input
 func (_m *RoomsManager) Find(ctx context.Context, input *port.FindRoomsInput) (*port.FindRoomsOutput, error) {
 	ret := _m.Called(ctx, input)
@@ -74,6 +134,156 @@ func (_m *RoomsManager) Find(ctx context.Context, input *port.FindRoomsInput) (*
 	return r0, r1
 }
 
+// FindMembers provides a mock function with given fields: ctx, input
+func (_m *RoomsManager) FindMembers(ctx context.Context, input *port.FindRoomMembersInput) (*port.FindRoomMembersOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMembers")
+	}
+
+	var r0 *port.FindRoomMembersOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.FindRoomMembersInput) (*port.FindRoomMembersOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.FindRoomMembersInput) *port.FindRoomMembersOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.FindRoomMembersOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.FindRoomMembersInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, input
+func (_m *RoomsManager) Get(ctx context.Context, input *port.GetRoomInput) (*port.GetRoomOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *port.GetRoomOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetRoomInput) (*port.GetRoomOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetRoomInput) *port.GetRoomOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.GetRoomOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.GetRoomInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMember provides a mock function with given fields: ctx, input
+func (_m *RoomsManager) GetMember(ctx context.Context, input *port.GetRoomMemberInput) (*port.GetRoomMemberOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMember")
+	}
+
+	var r0 *port.GetRoomMemberOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetRoomMemberInput) (*port.GetRoomMemberOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetRoomMemberInput) *port.GetRoomMemberOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.GetRoomMemberOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.GetRoomMemberInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveMember provides a mock function with given fields: ctx, input
+func (_m *RoomsManager) RemoveMember(ctx context.Context, input *port.RemoveRoomMemberInput) (*port.RemoveRoomMemberOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveMember")
+	}
+
+	var r0 *port.RemoveRoomMemberOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.RemoveRoomMemberInput) (*port.RemoveRoomMemberOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.RemoveRoomMemberInput) *port.RemoveRoomMemberOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.RemoveRoomMemberOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.RemoveRoomMemberInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, input
+func (_m *RoomsManager) Update(ctx context.Context, input *port.UpdateRoomInput) (*port.UpdateRoomOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *port.UpdateRoomOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.UpdateRoomInput) (*port.UpdateRoomOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.UpdateRoomInput) *port.UpdateRoomOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.UpdateRoomOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.UpdateRoomInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewRoomsManager creates a new instance of RoomsManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRoomsManager(t interface {
@@ -1,4 +1,4 @@
-// Code generated by mockery v2.40.1. DO NOT EDIT.
+// Code generated by mockery v2.53.3. DO NOT EDIT.
 
 package mocks
 
@@ -44,6 +44,96 @@ func (_m *RoomsReader) Find(ctx context.Context, input *port.FindRoomsInput) (*p
 	return r0, r1
 }
 
+// FindMembers provides a mock function with given fields: ctx, input
+func (_m *RoomsReader) FindMembers(ctx context.Context, input *port.FindRoomMembersInput) (*port.FindRoomMembersOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMembers")
+	}
+
+	var r0 *port.FindRoomMembersOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.FindRoomMembersInput) (*port.FindRoomMembersOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.FindRoomMembersInput) *port.FindRoomMembersOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.FindRoomMembersOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.FindRoomMembersInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, input
+func (_m *RoomsReader) Get(ctx context.Context, input *port.GetRoomInput) (*port.GetRoomOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *port.GetRoomOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetRoomInput) (*port.GetRoomOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetRoomInput) *port.GetRoomOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.GetRoomOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.GetRoomInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMember provides a mock function with given fields: ctx, input
+func (_m *RoomsReader) GetMember(ctx context.Context, input *port.GetRoomMemberInput) (*port.GetRoomMemberOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMember")
+	}
+
+	var r0 *port.GetRoomMemberOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetRoomMemberInput) (*port.GetRoomMemberOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetRoomMemberInput) *port.GetRoomMemberOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.GetRoomMemberOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.GetRoomMemberInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewRoomsReader creates a new instance of RoomsReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRoomsReader(t interface {
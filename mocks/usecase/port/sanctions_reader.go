@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-ws-sample/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SanctionsReader is an autogenerated mock type for the SanctionsReader type
+type SanctionsReader struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: ctx, input
+func (_m *SanctionsReader) Get(ctx context.Context, input *port.GetSanctionInput) (*port.GetSanctionOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *port.GetSanctionOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetSanctionInput) (*port.GetSanctionOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetSanctionInput) *port.GetSanctionOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.GetSanctionOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.GetSanctionInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSanctionsReader creates a new instance of SanctionsReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSanctionsReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SanctionsReader {
+	mock := &SanctionsReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
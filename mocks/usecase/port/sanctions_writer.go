@@ -0,0 +1,89 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-ws-sample/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SanctionsWriter is an autogenerated mock type for the SanctionsWriter type
+type SanctionsWriter struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, input
+func (_m *SanctionsWriter) Delete(ctx context.Context, input *port.DeleteSanctionInput) (*port.DeleteSanctionOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *port.DeleteSanctionOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.DeleteSanctionInput) (*port.DeleteSanctionOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.DeleteSanctionInput) *port.DeleteSanctionOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.DeleteSanctionOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.DeleteSanctionInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Put provides a mock function with given fields: ctx, input
+func (_m *SanctionsWriter) Put(ctx context.Context, input *port.PutSanctionInput) (*port.PutSanctionOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Put")
+	}
+
+	var r0 *port.PutSanctionOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.PutSanctionInput) (*port.PutSanctionOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.PutSanctionInput) *port.PutSanctionOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.PutSanctionOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.PutSanctionInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSanctionsWriter creates a new instance of SanctionsWriter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSanctionsWriter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SanctionsWriter {
+	mock := &SanctionsWriter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-ws-sample/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TokenManager is an autogenerated mock type for the TokenManager type
+type TokenManager struct {
+	mock.Mock
+}
+
+// Issue provides a mock function with given fields: ctx, input
+func (_m *TokenManager) Issue(ctx context.Context, input *port.IssueTokenInput) (*port.IssueTokenOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Issue")
+	}
+
+	var r0 *port.IssueTokenOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.IssueTokenInput) (*port.IssueTokenOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.IssueTokenInput) *port.IssueTokenOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.IssueTokenOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.IssueTokenInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, input
+func (_m *TokenManager) Verify(ctx context.Context, input *port.VerifyTokenInput) (*port.VerifyTokenOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 *port.VerifyTokenOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.VerifyTokenInput) (*port.VerifyTokenOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.VerifyTokenInput) *port.VerifyTokenOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.VerifyTokenOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.VerifyTokenInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTokenManager creates a new instance of TokenManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenManager {
+	mock := &TokenManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-ws-sample/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UsersReader is an autogenerated mock type for the UsersReader type
+type UsersReader struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: ctx, input
+func (_m *UsersReader) Get(ctx context.Context, input *port.GetUserInput) (*port.GetUserOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *port.GetUserOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetUserInput) (*port.GetUserOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetUserInput) *port.GetUserOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.GetUserOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.GetUserInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCredential provides a mock function with given fields: ctx, input
+func (_m *UsersReader) GetCredential(ctx context.Context, input *port.GetUserCredentialInput) (*port.GetUserCredentialOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCredential")
+	}
+
+	var r0 *port.GetUserCredentialOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetUserCredentialInput) (*port.GetUserCredentialOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.GetUserCredentialInput) *port.GetUserCredentialOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.GetUserCredentialOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.GetUserCredentialInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewUsersReader creates a new instance of UsersReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUsersReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UsersReader {
+	mock := &UsersReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-ws-sample/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UsersWriter is an autogenerated mock type for the UsersWriter type
+type UsersWriter struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, input
+func (_m *UsersWriter) Create(ctx context.Context, input *port.CreateUserInput) (*port.CreateUserOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *port.CreateUserOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *port.CreateUserInput) (*port.CreateUserOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *port.CreateUserInput) *port.CreateUserOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.CreateUserOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *port.CreateUserInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewUsersWriter creates a new instance of UsersWriter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUsersWriter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UsersWriter {
+	mock := &UsersWriter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
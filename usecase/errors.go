@@ -7,3 +7,18 @@ var ErrInvalidCredential = errors.New("invalid credential")
 
 var ErrNotFoundEntity = errors.New("not found entity")
 var ErrAlreadyExistsEntity = errors.New("already exists entity")
+
+// ErrForbidden is returned when the role of a user in a room does not allow the action.
+var ErrForbidden = errors.New("forbidden")
+
+// ErrMuted is returned when a user muted in a room posts a message to it.
+var ErrMuted = errors.New("muted")
+
+// ErrVersionConflict is returned when an entity was changed after the version the caller expected.
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrInvalidCursor is returned when a pagination cursor was not issued for the request.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrRateLimited is returned when a caller exceeds its rate limit.
+var ErrRateLimited = errors.New("rate limited")
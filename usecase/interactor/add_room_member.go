@@ -0,0 +1,93 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/policy"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ AddRoomMemberInteractor = (*addRoomMemberInteractor)(nil)
+
+type (
+	AddRoomMemberInput struct {
+		RoomID entity.ID
+		UserID entity.ID
+		// Role defaults to entity.RoleMember.
+		Role entity.Role
+		// ActorID is the user requesting it, whose role in the room must allow it.
+		ActorID entity.ID
+	}
+	AddRoomMemberOutput struct {
+		Member *entity.Member
+	}
+	// AddRoomMemberInteractor adds a user to a room, ErrForbidden is returned while the user is banned from it.
+	AddRoomMemberInteractor interface {
+		Add(ctx context.Context, input *AddRoomMemberInput) (*AddRoomMemberOutput, error)
+	}
+	addRoomMemberInteractor struct {
+		rooms     port.RoomsManager
+		users     port.UsersReader
+		sanctions port.SanctionsReader
+	}
+)
+
+func NewAddRoomMemberInteractor(rooms port.RoomsManager, users port.UsersReader, sanctions port.SanctionsReader) *addRoomMemberInteractor {
+	return &addRoomMemberInteractor{
+		rooms:     rooms,
+		users:     users,
+		sanctions: sanctions,
+	}
+}
+
+func (it *addRoomMemberInteractor) Add(ctx context.Context, input *AddRoomMemberInput) (*AddRoomMemberOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.AddRoomMemberInteractor.Add")
+	defer span.End()
+	role := input.Role
+	if len(role) == 0 {
+		role = entity.RoleMember
+	}
+	roomOut, err := it.rooms.Get(ctx, &port.GetRoomInput{
+		ID: input.RoomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.AuthorizeAddMember(roomOut.Room, input.ActorID, role); err != nil {
+		return nil, err
+	}
+
+	userOut, err := it.users.Get(ctx, &port.GetUserInput{
+		ID: input.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = it.sanctions.Get(ctx, &port.GetSanctionInput{
+		RoomID: input.RoomID,
+		UserID: input.UserID,
+		Type:   entity.SanctionTypeBan,
+	})
+	if err == nil {
+		return nil, usecase.ErrForbidden
+	}
+	if !errors.Is(err, usecase.ErrNotFoundEntity) {
+		return nil, err
+	}
+	out, err := it.rooms.AddMember(ctx, &port.AddRoomMemberInput{
+		RoomID: input.RoomID,
+		User:   userOut.User,
+		Role:   role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddRoomMemberOutput{
+		Member: out.Member,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddRoomMemberInteractor_Add(t *testing.T) {
+	owner := &entity.User{ID: "01HNZ0000000000000000000U1", Name: "owner"}
+	moderator := &entity.User{ID: "01HNZ0000000000000000000U2", Name: "moderator"}
+	member := &entity.User{ID: "01HNZ0000000000000000000U3", Name: "member"}
+	user := &entity.User{ID: "01HNZ0000000000000000000U4", Name: "user"}
+	room := &entity.Room{ID: "01HNZ0000000000000000000AA", Name: "room", Members: entity.Members{
+		{User: owner, Role: entity.RoleOwner},
+		{User: moderator, Role: entity.RoleModerator},
+		{User: member, Role: entity.RoleMember},
+	}}
+	tests := []struct {
+		name     string
+		actorID  entity.ID
+		role     entity.Role
+		userErr  error
+		banned   bool
+		wantRole entity.Role
+		wantErr  error
+	}{
+		{
+			name:     "add user as member by default",
+			actorID:  moderator.ID,
+			wantRole: entity.RoleMember,
+		},
+		{
+			name:     "owner adds moderator",
+			actorID:  owner.ID,
+			role:     entity.RoleModerator,
+			wantRole: entity.RoleModerator,
+		},
+		{
+			name:    "return ErrForbidden when moderator adds moderator",
+			actorID: moderator.ID,
+			role:    entity.RoleModerator,
+			wantErr: usecase.ErrForbidden,
+		},
+		{
+			name:    "return ErrForbidden when member adds user",
+			actorID: member.ID,
+			wantErr: usecase.ErrForbidden,
+		},
+		{
+			name:    "return ErrForbidden when user is banned",
+			actorID: owner.ID,
+			banned:  true,
+			wantErr: usecase.ErrForbidden,
+		},
+		{
+			name:    "return ErrNotFoundEntity when user does not exist",
+			actorID: moderator.ID,
+			userErr: usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rooms := mocks.NewRoomsManager(t)
+			users := mocks.NewUsersReader(t)
+			sanctions := mocks.NewSanctionsReader(t)
+			rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: room.ID}).Return(&port.GetRoomOutput{Room: room}, nil)
+			if tt.userErr != nil {
+				users.On("Get", mock.Anything, &port.GetUserInput{ID: user.ID}).Return(nil, tt.userErr)
+			} else if tt.wantErr == nil || tt.banned {
+				users.On("Get", mock.Anything, &port.GetUserInput{ID: user.ID}).Return(&port.GetUserOutput{User: user}, nil)
+				banErr := usecase.ErrNotFoundEntity
+				if tt.banned {
+					banErr = nil
+				}
+				sanctions.On("Get", mock.Anything, &port.GetSanctionInput{RoomID: room.ID, UserID: user.ID, Type: entity.SanctionTypeBan}).
+					Return(&port.GetSanctionOutput{}, banErr)
+			}
+			want := &entity.Member{User: user, Role: tt.wantRole}
+			if tt.wantErr == nil {
+				rooms.On("AddMember", mock.Anything, &port.AddRoomMemberInput{RoomID: room.ID, User: user, Role: tt.wantRole}).
+					Return(&port.AddRoomMemberOutput{Member: want}, nil)
+			}
+
+			it := NewAddRoomMemberInteractor(rooms, users, sanctions)
+			got, err := it.Add(ctx, &AddRoomMemberInput{RoomID: room.ID, UserID: user.ID, Role: tt.role, ActorID: tt.actorID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &AddRoomMemberOutput{Member: want}, got)
+		})
+	}
+}
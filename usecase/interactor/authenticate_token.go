@@ -0,0 +1,60 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ AuthenticateTokenInteractor = (*authenticateTokenInteractor)(nil)
+
+type (
+	AuthenticateTokenInput struct {
+		Token string
+	}
+	AuthenticateTokenOutput struct {
+		User *entity.User
+	}
+	AuthenticateTokenInteractor interface {
+		Authenticate(ctx context.Context, input *AuthenticateTokenInput) (*AuthenticateTokenOutput, error)
+	}
+	authenticateTokenInteractor struct {
+		users  port.UsersReader
+		tokens port.TokenManager
+	}
+)
+
+func NewAuthenticateTokenInteractor(users port.UsersReader, tokens port.TokenManager) *authenticateTokenInteractor {
+	return &authenticateTokenInteractor{
+		users:  users,
+		tokens: tokens,
+	}
+}
+
+func (it *authenticateTokenInteractor) Authenticate(ctx context.Context, input *AuthenticateTokenInput) (*AuthenticateTokenOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.AuthenticateTokenInteractor.Authenticate")
+	defer span.End()
+	verified, err := it.tokens.Verify(ctx, &port.VerifyTokenInput{
+		Token: input.Token,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out, err := it.users.Get(ctx, &port.GetUserInput{
+		ID: verified.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFoundEntity) {
+			return nil, usecase.ErrNoAuthUser
+		}
+		return nil, err
+	}
+
+	return &AuthenticateTokenOutput{
+		User: out.User,
+	}, nil
+}
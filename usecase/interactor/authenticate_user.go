@@ -0,0 +1,58 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ AuthenticateUserInteractor = (*authenticateUserInteractor)(nil)
+
+type (
+	AuthenticateUserInput struct {
+		Name     string
+		Password string
+	}
+	AuthenticateUserOutput struct {
+		User *entity.User
+	}
+	AuthenticateUserInteractor interface {
+		Authenticate(ctx context.Context, input *AuthenticateUserInput) (*AuthenticateUserOutput, error)
+	}
+	authenticateUserInteractor struct {
+		users  port.UsersReader
+		hasher port.PasswordHasher
+	}
+)
+
+func NewAuthenticateUserInteractor(users port.UsersReader, hasher port.PasswordHasher) *authenticateUserInteractor {
+	return &authenticateUserInteractor{
+		users:  users,
+		hasher: hasher,
+	}
+}
+
+func (it *authenticateUserInteractor) Authenticate(ctx context.Context, input *AuthenticateUserInput) (*AuthenticateUserOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.AuthenticateUserInteractor.Authenticate")
+	defer span.End()
+	out, err := it.users.GetCredential(ctx, &port.GetUserCredentialInput{
+		Name: input.Name,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFoundEntity) {
+			return nil, usecase.ErrNoAuthUser
+		}
+		return nil, err
+	}
+	if err := it.hasher.Compare(ctx, out.PasswordHash, input.Password); err != nil {
+		return nil, err
+	}
+
+	return &AuthenticateUserOutput{
+		User: out.User,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthenticateUserInteractor_Authenticate(t *testing.T) {
+	user := &entity.User{ID: "01HNZ0000000000000000000AA", Name: "alice"}
+	hash := []byte("hash")
+	type args struct {
+		input *AuthenticateUserInput
+	}
+	tests := []struct {
+		name       string
+		args       args
+		credential *port.GetUserCredentialOutput
+		findErr    error
+		compareErr error
+		want       *AuthenticateUserOutput
+		wantErr    error
+	}{
+		{
+			name: "return user when password matches",
+			args: args{
+				input: &AuthenticateUserInput{Name: "alice", Password: "password"},
+			},
+			credential: &port.GetUserCredentialOutput{User: user, PasswordHash: hash},
+			want:       &AuthenticateUserOutput{User: user},
+		},
+		{
+			name: "return ErrNoAuthUser when user does not exist",
+			args: args{
+				input: &AuthenticateUserInput{Name: "bob", Password: "password"},
+			},
+			findErr: usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNoAuthUser,
+		},
+		{
+			name: "return ErrInvalidCredential when password does not match",
+			args: args{
+				input: &AuthenticateUserInput{Name: "alice", Password: "wrong"},
+			},
+			credential: &port.GetUserCredentialOutput{User: user, PasswordHash: hash},
+			compareErr: usecase.ErrInvalidCredential,
+			wantErr:    usecase.ErrInvalidCredential,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users := mocks.NewUsersReader(t)
+			users.On("GetCredential", mock.Anything, &port.GetUserCredentialInput{Name: tt.args.input.Name}).
+				Return(tt.credential, tt.findErr)
+			hasher := mocks.NewPasswordHasher(t)
+			if tt.findErr == nil {
+				hasher.On("Compare", mock.Anything, hash, tt.args.input.Password).Return(tt.compareErr)
+			}
+
+			it := NewAuthenticateUserInteractor(users, hasher)
+			got, err := it.Authenticate(ctx, tt.args.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
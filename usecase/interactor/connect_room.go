@@ -0,0 +1,51 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ ConnectRoomInteractor = (*connectRoomInteractor)(nil)
+
+type (
+	ConnectRoomInput struct {
+		RoomID entity.ID
+		UserID entity.ID
+		Conn   port.RoomHubConn
+	}
+	ConnectRoomOutput struct {
+		ConnID entity.ID
+	}
+	ConnectRoomInteractor interface {
+		Connect(ctx context.Context, input *ConnectRoomInput) (*ConnectRoomOutput, error)
+	}
+	connectRoomInteractor struct {
+		hub port.RoomHub
+	}
+)
+
+func NewConnectRoomInteractor(hub port.RoomHub) *connectRoomInteractor {
+	return &connectRoomInteractor{
+		hub: hub,
+	}
+}
+
+func (it *connectRoomInteractor) Connect(ctx context.Context, input *ConnectRoomInput) (*ConnectRoomOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.ConnectRoomInteractor.Connect")
+	defer span.End()
+	out, err := it.hub.Join(ctx, &port.JoinRoomHubInput{
+		RoomID: input.RoomID,
+		UserID: input.UserID,
+		Conn:   input.Conn,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConnectRoomOutput{
+		ConnID: out.ConnID,
+	}, nil
+}
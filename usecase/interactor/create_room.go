@@ -5,6 +5,7 @@ import (
 
 	"github.com/mkaiho/go-ws-sample/entity"
 	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
 )
 
 var _ CreateRoomInteractor = (*createRoomInteractor)(nil)
@@ -13,6 +14,8 @@ type (
 	CreateRoomInput struct {
 		Name        string
 		Description *string
+		// Owner is the user creating the room, who becomes its owner.
+		Owner *entity.User
 	}
 	CreateRoomOutput struct {
 		Room *entity.Room
@@ -32,9 +35,12 @@ func NewCreateRoomInteractor(rooms port.RoomsManager) *createRoomInteractor {
 }
 
 func (it *createRoomInteractor) Create(ctx context.Context, input *CreateRoomInput) (*CreateRoomOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.CreateRoomInteractor.Create")
+	defer span.End()
 	out, err := it.rooms.Create(ctx, &port.CreateRoomInput{
 		Name:        input.Name,
 		Description: input.Description,
+		Owner:       input.Owner,
 	})
 	if err != nil {
 		return nil, err
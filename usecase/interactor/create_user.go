@@ -0,0 +1,56 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ CreateUserInteractor = (*createUserInteractor)(nil)
+
+type (
+	CreateUserInput struct {
+		Name     string
+		Password string
+	}
+	CreateUserOutput struct {
+		User *entity.User
+	}
+	CreateUserInteractor interface {
+		Create(ctx context.Context, input *CreateUserInput) (*CreateUserOutput, error)
+	}
+	createUserInteractor struct {
+		users  port.UsersManager
+		hasher port.PasswordHasher
+	}
+)
+
+func NewCreateUserInteractor(users port.UsersManager, hasher port.PasswordHasher) *createUserInteractor {
+	return &createUserInteractor{
+		users:  users,
+		hasher: hasher,
+	}
+}
+
+func (it *createUserInteractor) Create(ctx context.Context, input *CreateUserInput) (*CreateUserOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.CreateUserInteractor.Create")
+	defer span.End()
+	hash, err := it.hasher.Hash(ctx, input.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	out, err := it.users.Create(ctx, &port.CreateUserInput{
+		Name:         input.Name,
+		PasswordHash: hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateUserOutput{
+		User: out.User,
+	}, nil
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/policy"
 	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
 )
 
 var _ DeleteRoomInteractor = (*deleteRoomInteractor)(nil)
@@ -12,6 +14,10 @@ var _ DeleteRoomInteractor = (*deleteRoomInteractor)(nil)
 type (
 	DeleteRoomInput struct {
 		ID entity.ID
+		// Version is the version the room must have, any version is accepted when nil.
+		Version *int
+		// ActorID is the user requesting it, whose role in the room must allow it.
+		ActorID entity.ID
 	}
 	DeleteRoomOutput struct {
 		Room *entity.Room
@@ -21,22 +27,49 @@ type (
 	}
 	deleteRoomInteractor struct {
 		rooms port.RoomsManager
+		hub   port.RoomHub
 	}
 )
 
-func NewDeleteRoomInteractor(rooms port.RoomsManager) *deleteRoomInteractor {
+// NewDeleteRoomInteractor deletes rooms through rooms, whose Delete removes the messages of the room as well.
+func NewDeleteRoomInteractor(rooms port.RoomsManager, hub port.RoomHub) *deleteRoomInteractor {
 	return &deleteRoomInteractor{
 		rooms: rooms,
+		hub:   hub,
 	}
 }
 
 func (it *deleteRoomInteractor) Delete(ctx context.Context, input *DeleteRoomInput) (*DeleteRoomOutput, error) {
-	_, err := it.rooms.Delete(ctx, &port.DeleteRoomInput{
+	ctx, span := util.StartSpan(ctx, "interactor.DeleteRoomInteractor.Delete")
+	defer span.End()
+	roomOut, err := it.rooms.Get(ctx, &port.GetRoomInput{
 		ID: input.ID,
 	})
 	if err != nil {
 		return nil, err
 	}
+	if err := policy.AuthorizeRoom(roomOut.Room, input.ActorID, policy.RoomActionDelete); err != nil {
+		return nil, err
+	}
+
+	_, err = it.rooms.Delete(ctx, &port.DeleteRoomInput{
+		ID:      input.ID,
+		Version: input.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the connections of the room are closed once they receive the event
+	_, err = it.hub.Broadcast(ctx, &port.BroadcastRoomHubInput{
+		Event: &port.RoomEvent{
+			Type:   port.RoomEventTypeRoomDeleted,
+			RoomID: input.ID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return &DeleteRoomOutput{}, nil
 }
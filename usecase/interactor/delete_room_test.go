@@ -0,0 +1,86 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeleteRoomInteractor_Delete(t *testing.T) {
+	owner := &entity.User{ID: "01HNZ0000000000000000000U1", Name: "owner"}
+	moderator := &entity.User{ID: "01HNZ0000000000000000000U2", Name: "moderator"}
+	room := &entity.Room{
+		ID:   "01HNZ0000000000000000000AA",
+		Name: "room",
+		Members: entity.Members{
+			{User: owner, Role: entity.RoleOwner},
+			{User: moderator, Role: entity.RoleModerator},
+		},
+	}
+	tests := []struct {
+		name      string
+		actorID   entity.ID
+		getErr    error
+		deleteErr error
+		wantErr   error
+	}{
+		{
+			name:    "close connections of deleted room",
+			actorID: owner.ID,
+		},
+		{
+			name:    "return ErrNotFoundEntity when room does not exist",
+			actorID: owner.ID,
+			getErr:  usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+		{
+			name:    "return ErrForbidden without deleting when user is not the owner",
+			actorID: moderator.ID,
+			wantErr: usecase.ErrForbidden,
+		},
+		{
+			name:      "return ErrVersionConflict when room was changed",
+			actorID:   owner.ID,
+			deleteErr: usecase.ErrVersionConflict,
+			wantErr:   usecase.ErrVersionConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rooms := mocks.NewRoomsManager(t)
+			hub := mocks.NewRoomHub(t)
+			if tt.getErr != nil {
+				rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: room.ID}).Return(nil, tt.getErr)
+			} else {
+				rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: room.ID}).Return(&port.GetRoomOutput{Room: room}, nil)
+			}
+			switch {
+			case tt.getErr != nil || tt.actorID != owner.ID:
+			case tt.deleteErr != nil:
+				rooms.On("Delete", mock.Anything, &port.DeleteRoomInput{ID: room.ID}).Return(nil, tt.deleteErr)
+			default:
+				rooms.On("Delete", mock.Anything, &port.DeleteRoomInput{ID: room.ID}).Return(&port.DeleteRoomOutput{}, nil)
+				hub.On("Broadcast", mock.Anything, &port.BroadcastRoomHubInput{
+					Event: &port.RoomEvent{Type: port.RoomEventTypeRoomDeleted, RoomID: room.ID},
+				}).Return(&port.BroadcastRoomHubOutput{}, nil)
+			}
+
+			it := NewDeleteRoomInteractor(rooms, hub)
+			got, err := it.Delete(ctx, &DeleteRoomInput{ID: room.ID, ActorID: tt.actorID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &DeleteRoomOutput{}, got)
+		})
+	}
+}
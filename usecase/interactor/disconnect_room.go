@@ -0,0 +1,45 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ DisconnectRoomInteractor = (*disconnectRoomInteractor)(nil)
+
+type (
+	DisconnectRoomInput struct {
+		RoomID entity.ID
+		ConnID entity.ID
+	}
+	DisconnectRoomOutput     struct{}
+	DisconnectRoomInteractor interface {
+		Disconnect(ctx context.Context, input *DisconnectRoomInput) (*DisconnectRoomOutput, error)
+	}
+	disconnectRoomInteractor struct {
+		hub port.RoomHub
+	}
+)
+
+func NewDisconnectRoomInteractor(hub port.RoomHub) *disconnectRoomInteractor {
+	return &disconnectRoomInteractor{
+		hub: hub,
+	}
+}
+
+func (it *disconnectRoomInteractor) Disconnect(ctx context.Context, input *DisconnectRoomInput) (*DisconnectRoomOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.DisconnectRoomInteractor.Disconnect")
+	defer span.End()
+	_, err := it.hub.Leave(ctx, &port.LeaveRoomHubInput{
+		RoomID: input.RoomID,
+		ConnID: input.ConnID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DisconnectRoomOutput{}, nil
+}
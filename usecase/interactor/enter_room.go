@@ -0,0 +1,58 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ EnterRoomInteractor = (*enterRoomInteractor)(nil)
+
+type (
+	EnterRoomInput struct {
+		RoomID entity.ID
+		UserID entity.ID
+	}
+	EnterRoomOutput struct {
+		Room   *entity.Room
+		Member *entity.Member
+	}
+	// EnterRoomInteractor checks that a user may open the message stream of a room.
+	// Rooms the user is not a member of are reported as not found, and ErrForbidden is returned while the user is banned.
+	EnterRoomInteractor interface {
+		Enter(ctx context.Context, input *EnterRoomInput) (*EnterRoomOutput, error)
+	}
+	enterRoomInteractor struct {
+		rooms     port.RoomsReader
+		sanctions port.SanctionsReader
+	}
+)
+
+func NewEnterRoomInteractor(rooms port.RoomsReader, sanctions port.SanctionsReader) *enterRoomInteractor {
+	return &enterRoomInteractor{
+		rooms:     rooms,
+		sanctions: sanctions,
+	}
+}
+
+func (it *enterRoomInteractor) Enter(ctx context.Context, input *EnterRoomInput) (*EnterRoomOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.EnterRoomInteractor.Enter")
+	defer span.End()
+	roomOut, err := it.rooms.Get(ctx, &port.GetRoomInput{
+		ID: input.RoomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	member, err := getReadingMember(ctx, it.rooms, it.sanctions, input.RoomID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EnterRoomOutput{
+		Room:   roomOut.Room,
+		Member: member,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEnterRoomInteractor_Enter(t *testing.T) {
+	owner := &entity.User{ID: "01HNZ0000000000000000000U1", Name: "owner"}
+	member := &entity.Member{User: &entity.User{ID: "01HNZ0000000000000000000U2", Name: "member"}, Role: entity.RoleMember}
+	room := &entity.Room{ID: "01HNZ0000000000000000000AA", Name: "room", Members: entity.Members{
+		{User: owner, Role: entity.RoleOwner},
+		member,
+	}}
+	tests := []struct {
+		name      string
+		getErr    error
+		memberErr error
+		banned    bool
+		want      *EnterRoomOutput
+		wantErr   error
+	}{
+		{
+			name: "let a member enter",
+			want: &EnterRoomOutput{Room: room, Member: member},
+		},
+		{
+			name:    "return ErrNotFoundEntity when room does not exist",
+			getErr:  usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+		{
+			name:      "return ErrNotFoundEntity when user is not a member",
+			memberErr: usecase.ErrNotFoundEntity,
+			wantErr:   usecase.ErrNotFoundEntity,
+		},
+		{
+			name:    "return ErrForbidden when member is banned",
+			banned:  true,
+			wantErr: usecase.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rooms := mocks.NewRoomsReader(t)
+			sanctions := mocks.NewSanctionsReader(t)
+			if tt.getErr != nil {
+				rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: room.ID}).Return(nil, tt.getErr)
+			} else {
+				rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: room.ID}).Return(&port.GetRoomOutput{Room: room}, nil)
+				if tt.memberErr != nil {
+					rooms.On("GetMember", mock.Anything, &port.GetRoomMemberInput{RoomID: room.ID, UserID: member.User.ID}).Return(nil, tt.memberErr)
+				} else {
+					rooms.On("GetMember", mock.Anything, &port.GetRoomMemberInput{RoomID: room.ID, UserID: member.User.ID}).
+						Return(&port.GetRoomMemberOutput{Member: member}, nil)
+				}
+			}
+			if tt.getErr == nil && tt.memberErr == nil {
+				banErr := usecase.ErrNotFoundEntity
+				if tt.banned {
+					banErr = nil
+				}
+				sanctions.On("Get", mock.Anything, &port.GetSanctionInput{RoomID: room.ID, UserID: member.User.ID, Type: entity.SanctionTypeBan}).
+					Return(&port.GetSanctionOutput{}, banErr)
+			}
+
+			it := NewEnterRoomInteractor(rooms, sanctions)
+			got, err := it.Enter(ctx, &EnterRoomInput{RoomID: room.ID, UserID: member.User.ID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
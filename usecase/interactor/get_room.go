@@ -5,6 +5,7 @@ import (
 
 	"github.com/mkaiho/go-ws-sample/entity"
 	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
 )
 
 var _ GetRoomInteractor = (*getRoomsInteractor)(nil)
@@ -31,6 +32,8 @@ func NewGetRoomInteractor(rooms port.RoomsReader) *getRoomsInteractor {
 }
 
 func (it *getRoomsInteractor) Get(ctx context.Context, input *GetRoomInput) (*GetRoomOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.GetRoomsInteractor.Get")
+	defer span.End()
 	out, err := it.rooms.Get(ctx, &port.GetRoomInput{
 		ID: input.ID,
 	})
@@ -0,0 +1,50 @@
+package interactor
+
+import (
+	"context"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ IssueTokenInteractor = (*issueTokenInteractor)(nil)
+
+type (
+	IssueTokenInput struct {
+		User *entity.User
+	}
+	IssueTokenOutput struct {
+		Token     string
+		ExpiresAt time.Time
+	}
+	IssueTokenInteractor interface {
+		Issue(ctx context.Context, input *IssueTokenInput) (*IssueTokenOutput, error)
+	}
+	issueTokenInteractor struct {
+		tokens port.TokenManager
+	}
+)
+
+func NewIssueTokenInteractor(tokens port.TokenManager) *issueTokenInteractor {
+	return &issueTokenInteractor{
+		tokens: tokens,
+	}
+}
+
+func (it *issueTokenInteractor) Issue(ctx context.Context, input *IssueTokenInput) (*IssueTokenOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.IssueTokenInteractor.Issue")
+	defer span.End()
+	out, err := it.tokens.Issue(ctx, &port.IssueTokenInput{
+		User: input.User,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssueTokenOutput{
+		Token:     out.Token,
+		ExpiresAt: out.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/policy"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ KickMemberInteractor = (*kickMemberInteractor)(nil)
+
+type (
+	KickMemberInput struct {
+		RoomID entity.ID
+		UserID entity.ID
+		// ActorID is the user requesting it, whose role in the room must allow it.
+		ActorID entity.ID
+	}
+	KickMemberOutput struct{}
+	// KickMemberInteractor closes the connections of a member to the room on every instance.
+	// The member stays in the room and may connect again.
+	KickMemberInteractor interface {
+		Kick(ctx context.Context, input *KickMemberInput) (*KickMemberOutput, error)
+	}
+	kickMemberInteractor struct {
+		rooms port.RoomsReader
+		hub   port.RoomHub
+	}
+)
+
+func NewKickMemberInteractor(rooms port.RoomsReader, hub port.RoomHub) *kickMemberInteractor {
+	return &kickMemberInteractor{
+		rooms: rooms,
+		hub:   hub,
+	}
+}
+
+func (it *kickMemberInteractor) Kick(ctx context.Context, input *KickMemberInput) (*KickMemberOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.KickMemberInteractor.Kick")
+	defer span.End()
+	roomOut, err := it.rooms.Get(ctx, &port.GetRoomInput{
+		ID: input.RoomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.AuthorizeModerate(roomOut.Room, input.ActorID, input.UserID); err != nil {
+		return nil, err
+	}
+	if roomOut.Room.Members.Find(input.UserID) == nil {
+		return nil, usecase.ErrNotFoundEntity
+	}
+
+	_, err = it.hub.Broadcast(ctx, &port.BroadcastRoomHubInput{
+		Event: &port.RoomEvent{
+			Type:   port.RoomEventTypeUserKicked,
+			RoomID: input.RoomID,
+			UserID: input.UserID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &KickMemberOutput{}, nil
+}
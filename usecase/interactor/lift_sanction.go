@@ -0,0 +1,62 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/policy"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ LiftSanctionInteractor = (*liftSanctionInteractor)(nil)
+
+type (
+	LiftSanctionInput struct {
+		RoomID entity.ID
+		UserID entity.ID
+		Type   entity.SanctionType
+		// ActorID is the user requesting it, whose role in the room must allow it.
+		ActorID entity.ID
+	}
+	LiftSanctionOutput     struct{}
+	LiftSanctionInteractor interface {
+		Lift(ctx context.Context, input *LiftSanctionInput) (*LiftSanctionOutput, error)
+	}
+	liftSanctionInteractor struct {
+		rooms     port.RoomsReader
+		sanctions port.SanctionsWriter
+	}
+)
+
+func NewLiftSanctionInteractor(rooms port.RoomsReader, sanctions port.SanctionsWriter) *liftSanctionInteractor {
+	return &liftSanctionInteractor{
+		rooms:     rooms,
+		sanctions: sanctions,
+	}
+}
+
+func (it *liftSanctionInteractor) Lift(ctx context.Context, input *LiftSanctionInput) (*LiftSanctionOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.LiftSanctionInteractor.Lift")
+	defer span.End()
+	roomOut, err := it.rooms.Get(ctx, &port.GetRoomInput{
+		ID: input.RoomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.AuthorizeModerate(roomOut.Room, input.ActorID, input.UserID); err != nil {
+		return nil, err
+	}
+
+	_, err = it.sanctions.Delete(ctx, &port.DeleteSanctionInput{
+		RoomID: input.RoomID,
+		UserID: input.UserID,
+		Type:   input.Type,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &LiftSanctionOutput{}, nil
+}
@@ -0,0 +1,84 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+const defaultListMessagesLimit = 50
+
+var _ ListMessagesInteractor = (*listMessagesInteractor)(nil)
+
+type (
+	ListMessagesInput struct {
+		RoomID entity.ID
+		Cursor *entity.ID
+		Limit  int
+		// UserID is the user reading the history, ErrNotFoundEntity is returned unless they are a member
+		// like for a room which does not exist, and ErrForbidden while they are banned.
+		UserID entity.ID
+	}
+	ListMessagesOutput struct {
+		// Messages are ordered newest first.
+		Messages   entity.PostMessages
+		NextCursor *entity.ID
+	}
+	ListMessagesInteractor interface {
+		List(ctx context.Context, input *ListMessagesInput) (*ListMessagesOutput, error)
+	}
+	listMessagesInteractor struct {
+		rooms     port.RoomsReader
+		sanctions port.SanctionsReader
+		messages  port.MessagesReader
+	}
+)
+
+func NewListMessagesInteractor(rooms port.RoomsReader, sanctions port.SanctionsReader, messages port.MessagesReader) *listMessagesInteractor {
+	return &listMessagesInteractor{
+		rooms:     rooms,
+		sanctions: sanctions,
+		messages:  messages,
+	}
+}
+
+func (it *listMessagesInteractor) List(ctx context.Context, input *ListMessagesInput) (*ListMessagesOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.ListMessagesInteractor.List")
+	defer span.End()
+	if _, err := it.rooms.Get(ctx, &port.GetRoomInput{
+		ID: input.RoomID,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := getReadingMember(ctx, it.rooms, it.sanctions, input.RoomID, input.UserID); err != nil {
+		return nil, err
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListMessagesLimit
+	}
+	// one extra message tells whether an older page exists
+	out, err := it.messages.Find(ctx, &port.FindMessagesInput{
+		RoomID: input.RoomID,
+		Before: input.Cursor,
+		Limit:  limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := out.Messages
+	var nextCursor *entity.ID
+	if len(messages) > limit {
+		messages = messages[:limit]
+		nextCursor = &messages[limit-1].ID
+	}
+
+	return &ListMessagesOutput{
+		Messages:   messages,
+		NextCursor: nextCursor,
+	}, nil
+}
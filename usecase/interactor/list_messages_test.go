@@ -0,0 +1,122 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListMessagesInteractor_List(t *testing.T) {
+	roomID := entity.ID("room")
+	userID := entity.ID("user")
+	messages := entity.PostMessages{
+		{ID: "03", RoomID: roomID},
+		{ID: "02", RoomID: roomID},
+		{ID: "01", RoomID: roomID},
+	}
+	type args struct {
+		input *ListMessagesInput
+	}
+	tests := []struct {
+		name      string
+		args      args
+		roomErr   error
+		memberErr error
+		banned    bool
+		found     entity.PostMessages
+		wantLimit int
+		want      *ListMessagesOutput
+		wantErr   error
+	}{
+		{
+			name: "return next cursor when older messages remain",
+			args: args{
+				input: &ListMessagesInput{RoomID: roomID, Limit: 2, UserID: userID},
+			},
+			found:     messages,
+			wantLimit: 3,
+			want: &ListMessagesOutput{
+				Messages:   messages[:2],
+				NextCursor: util.ToPointer(entity.ID("02")),
+			},
+		},
+		{
+			name: "return no cursor on the last page",
+			args: args{
+				input: &ListMessagesInput{RoomID: roomID, Cursor: util.ToPointer(entity.ID("02")), UserID: userID},
+			},
+			found:     messages[2:],
+			wantLimit: defaultListMessagesLimit + 1,
+			want: &ListMessagesOutput{
+				Messages: messages[2:],
+			},
+		},
+		{
+			name: "return error when room does not exist",
+			args: args{
+				input: &ListMessagesInput{RoomID: roomID, UserID: userID},
+			},
+			roomErr: usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+		{
+			name: "return not found when user is not a member",
+			args: args{
+				input: &ListMessagesInput{RoomID: roomID, UserID: userID},
+			},
+			memberErr: usecase.ErrNotFoundEntity,
+			wantErr:   usecase.ErrNotFoundEntity,
+		},
+		{
+			name: "return forbidden when user is banned",
+			args: args{
+				input: &ListMessagesInput{RoomID: roomID, UserID: userID},
+			},
+			banned:  true,
+			wantErr: usecase.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rooms := mocks.NewRoomsReader(t)
+			rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: roomID}).
+				Return(&port.GetRoomOutput{Room: &entity.Room{ID: roomID}}, tt.roomErr)
+			sanctions := mocks.NewSanctionsReader(t)
+			if tt.roomErr == nil {
+				rooms.On("GetMember", mock.Anything, &port.GetRoomMemberInput{RoomID: roomID, UserID: userID}).
+					Return(&port.GetRoomMemberOutput{Member: &entity.Member{User: &entity.User{ID: userID}, Role: entity.RoleMember}}, tt.memberErr)
+			}
+			if tt.roomErr == nil && tt.memberErr == nil {
+				sanctionErr := usecase.ErrNotFoundEntity
+				if tt.banned {
+					sanctionErr = nil
+				}
+				sanctions.On("Get", mock.Anything, &port.GetSanctionInput{RoomID: roomID, UserID: userID, Type: entity.SanctionTypeBan}).
+					Return(&port.GetSanctionOutput{}, sanctionErr)
+			}
+			messagesReader := mocks.NewMessagesReader(t)
+			if tt.roomErr == nil && tt.memberErr == nil && !tt.banned {
+				messagesReader.On("Find", mock.Anything, mock.MatchedBy(func(in *port.FindMessagesInput) bool {
+					return in.RoomID == roomID && in.Limit == tt.wantLimit && in.Before == tt.args.input.Cursor
+				})).Return(&port.FindMessagesOutput{Messages: tt.found}, nil)
+			}
+
+			it := NewListMessagesInteractor(rooms, sanctions, messagesReader)
+			got, err := it.List(ctx, tt.args.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ ListRoomMembersInteractor = (*listRoomMembersInteractor)(nil)
+
+type (
+	ListRoomMembersInput struct {
+		RoomID entity.ID
+		// UserID is the user reading the members, ErrNotFoundEntity is returned unless they are a member
+		// like for a room which does not exist, and ErrForbidden while they are banned.
+		UserID entity.ID
+	}
+	ListRoomMembersOutput struct {
+		Members entity.Members
+	}
+	ListRoomMembersInteractor interface {
+		List(ctx context.Context, input *ListRoomMembersInput) (*ListRoomMembersOutput, error)
+	}
+	listRoomMembersInteractor struct {
+		rooms     port.RoomsReader
+		sanctions port.SanctionsReader
+	}
+)
+
+func NewListRoomMembersInteractor(rooms port.RoomsReader, sanctions port.SanctionsReader) *listRoomMembersInteractor {
+	return &listRoomMembersInteractor{
+		rooms:     rooms,
+		sanctions: sanctions,
+	}
+}
+
+func (it *listRoomMembersInteractor) List(ctx context.Context, input *ListRoomMembersInput) (*ListRoomMembersOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.ListRoomMembersInteractor.List")
+	defer span.End()
+	if _, err := it.rooms.Get(ctx, &port.GetRoomInput{
+		ID: input.RoomID,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := getReadingMember(ctx, it.rooms, it.sanctions, input.RoomID, input.UserID); err != nil {
+		return nil, err
+	}
+
+	out, err := it.rooms.FindMembers(ctx, &port.FindRoomMembersInput{
+		RoomID: input.RoomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListRoomMembersOutput{
+		Members: out.Members,
+	}, nil
+}
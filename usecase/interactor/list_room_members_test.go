@@ -0,0 +1,95 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListRoomMembersInteractor_List(t *testing.T) {
+	owner := &entity.User{ID: "01HNZ0000000000000000000U1", Name: "owner"}
+	member := &entity.User{ID: "01HNZ0000000000000000000U2", Name: "member"}
+	stranger := &entity.User{ID: "01HNZ0000000000000000000U3", Name: "stranger"}
+	members := entity.Members{
+		{User: owner, Role: entity.RoleOwner},
+		{User: member, Role: entity.RoleMember},
+	}
+	room := &entity.Room{ID: "01HNZ0000000000000000000AA", Name: "room", Members: members}
+	tests := []struct {
+		name    string
+		userID  entity.ID
+		getErr  error
+		banned  bool
+		want    *ListRoomMembersOutput
+		wantErr error
+	}{
+		{
+			name:   "return members to a member",
+			userID: member.ID,
+			want:   &ListRoomMembersOutput{Members: members},
+		},
+		{
+			name:    "return ErrNotFoundEntity when room does not exist",
+			userID:  member.ID,
+			getErr:  usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+		{
+			name:    "return ErrNotFoundEntity when user is not a member",
+			userID:  stranger.ID,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+		{
+			name:    "return ErrForbidden when user is banned",
+			userID:  member.ID,
+			banned:  true,
+			wantErr: usecase.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rooms := mocks.NewRoomsReader(t)
+			sanctions := mocks.NewSanctionsReader(t)
+			if tt.getErr != nil {
+				rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: room.ID}).Return(nil, tt.getErr)
+			} else {
+				rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: room.ID}).Return(&port.GetRoomOutput{Room: room}, nil)
+				rooms.On("GetMember", mock.Anything, mock.Anything).Return(
+					func(_ context.Context, in *port.GetRoomMemberInput) (*port.GetRoomMemberOutput, error) {
+						if m := members.Find(in.UserID); m != nil {
+							return &port.GetRoomMemberOutput{Member: m}, nil
+						}
+						return nil, usecase.ErrNotFoundEntity
+					})
+			}
+			if tt.getErr == nil && members.Find(tt.userID) != nil {
+				banErr := usecase.ErrNotFoundEntity
+				if tt.banned {
+					banErr = nil
+				}
+				sanctions.On("Get", mock.Anything, &port.GetSanctionInput{RoomID: room.ID, UserID: tt.userID, Type: entity.SanctionTypeBan}).
+					Return(&port.GetSanctionOutput{}, banErr)
+			}
+			if tt.wantErr == nil {
+				rooms.On("FindMembers", mock.Anything, &port.FindRoomMembersInput{RoomID: room.ID}).
+					Return(&port.FindRoomMembersOutput{Members: members}, nil)
+			}
+
+			it := NewListRoomMembersInteractor(rooms, sanctions)
+			got, err := it.List(ctx, &ListRoomMembersInput{RoomID: room.ID, UserID: tt.userID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
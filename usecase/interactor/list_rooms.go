@@ -2,18 +2,33 @@ package interactor
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 
 	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
 	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
 )
 
+const defaultListRoomsLimit = 50
+
 var _ ListRoomsInteractor = (*listRoomsInteractor)(nil)
 
 type (
 	ListRoomsInput struct {
+		// Query limits the result to rooms whose name contains it, ignoring case.
+		Query string
+		// Sort defaults to port.RoomsSortKeyCreated.
+		Sort port.RoomsSortKey
+		// Cursor is the NextCursor of the previous page.
+		Cursor *string
+		Limit  int
 	}
 	ListRoomsOutput struct {
 		Rooms entity.Rooms
+		// NextCursor is nil on the last page.
+		NextCursor *string
 	}
 	ListRoomsInteractor interface {
 		List(ctx context.Context, input *ListRoomsInput) (*ListRoomsOutput, error)
@@ -30,12 +45,88 @@ func NewListRoomsInteractor(rooms port.RoomsReader) *listRoomsInteractor {
 }
 
 func (it *listRoomsInteractor) List(ctx context.Context, input *ListRoomsInput) (*ListRoomsOutput, error) {
-	out, err := it.rooms.Find(ctx, &port.FindRoomsInput{})
+	ctx, span := util.StartSpan(ctx, "interactor.ListRoomsInteractor.List")
+	defer span.End()
+	sort := input.Sort
+	if len(sort) == 0 {
+		sort = port.RoomsSortKeyCreated
+	}
+	var after *port.RoomsPosition
+	if input.Cursor != nil {
+		c, err := decodeRoomsCursor(*input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// a position is meaningless in another order or filter
+		if c.Sort != sort || c.Query != input.Query {
+			return nil, usecase.ErrInvalidCursor
+		}
+		after = &port.RoomsPosition{ID: c.ID, Name: c.Name}
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListRoomsLimit
+	}
+	// one extra room tells whether a next page exists
+	out, err := it.rooms.Find(ctx, &port.FindRoomsInput{
+		Query: input.Query,
+		Sort:  sort,
+		After: after,
+		Limit: limit + 1,
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	rooms := out.Rooms
+	var nextCursor *string
+	if len(rooms) > limit {
+		rooms = rooms[:limit]
+		last := rooms[limit-1]
+		c, err := encodeRoomsCursor(&roomsCursor{
+			Sort:  sort,
+			Query: input.Query,
+			ID:    last.ID,
+			Name:  last.Name,
+		})
+		if err != nil {
+			return nil, err
+		}
+		nextCursor = &c
+	}
+
 	return &ListRoomsOutput{
-		Rooms: out.Rooms,
+		Rooms:      rooms,
+		NextCursor: nextCursor,
 	}, nil
 }
+
+// roomsCursor is the position of the last room of a page.
+// It is handed to clients as an opaque string.
+type roomsCursor struct {
+	Sort  port.RoomsSortKey `json:"s"`
+	Query string            `json:"q,omitempty"`
+	ID    entity.ID         `json:"i"`
+	Name  string            `json:"n,omitempty"`
+}
+
+func encodeRoomsCursor(c *roomsCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeRoomsCursor(s string) (*roomsCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, usecase.ErrInvalidCursor
+	}
+	var c roomsCursor
+	if err := json.Unmarshal(b, &c); err != nil || len(c.ID) == 0 {
+		return nil, usecase.ErrInvalidCursor
+	}
+	return &c, nil
+}
@@ -0,0 +1,49 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRoomsInteractor_List(t *testing.T) {
+	ctx := context.Background()
+	rooms := entity.Rooms{
+		{ID: "01HNZ0000000000000000000AA", Name: "alpha"},
+		{ID: "01HNZ0000000000000000000AB", Name: "beta"},
+		{ID: "01HNZ0000000000000000000AC", Name: "gamma"},
+	}
+	reader := mocks.NewRoomsReader(t)
+	reader.On("Find", mock.Anything, &port.FindRoomsInput{Query: "a", Sort: port.RoomsSortKeyName, Limit: 3}).
+		Return(&port.FindRoomsOutput{Rooms: rooms}, nil)
+	reader.On("Find", mock.Anything, &port.FindRoomsInput{
+		Query: "a",
+		Sort:  port.RoomsSortKeyName,
+		After: &port.RoomsPosition{ID: rooms[1].ID, Name: rooms[1].Name},
+		Limit: 3,
+	}).Return(&port.FindRoomsOutput{Rooms: rooms[2:]}, nil)
+	it := NewListRoomsInteractor(reader)
+
+	first, err := it.List(ctx, &ListRoomsInput{Query: "a", Sort: port.RoomsSortKeyName, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, rooms[:2], first.Rooms)
+	require.NotNil(t, first.NextCursor)
+
+	second, err := it.List(ctx, &ListRoomsInput{Query: "a", Sort: port.RoomsSortKeyName, Cursor: first.NextCursor, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, rooms[2:], second.Rooms)
+	assert.Nil(t, second.NextCursor)
+
+	// cursors only resume the listing they were issued for
+	_, err = it.List(ctx, &ListRoomsInput{Query: "a", Sort: port.RoomsSortKeyCreated, Cursor: first.NextCursor, Limit: 2})
+	assert.ErrorIs(t, err, usecase.ErrInvalidCursor)
+	_, err = it.List(ctx, &ListRoomsInput{Cursor: new(string)})
+	assert.ErrorIs(t, err, usecase.ErrInvalidCursor)
+}
@@ -0,0 +1,100 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ PostMessageInteractor = (*postMessageInteractor)(nil)
+
+type (
+	PostMessageInput struct {
+		RoomID entity.ID
+		ConnID entity.ID
+		Body   string
+		User   *entity.User
+		// IdempotencyKey makes retried posts return the message created by the first one.
+		IdempotencyKey string
+	}
+	PostMessageOutput struct {
+		Message *entity.PostMessage
+	}
+	// PostMessageInteractor returns ErrMuted while the user is muted in the room.
+	PostMessageInteractor interface {
+		Post(ctx context.Context, input *PostMessageInput) (*PostMessageOutput, error)
+	}
+	postMessageInteractor struct {
+		messages  port.MessagesWriter
+		sanctions port.SanctionsReader
+		hub       port.RoomHub
+		metrics   port.Metrics
+	}
+)
+
+func NewPostMessageInteractor(
+	messages port.MessagesWriter,
+	sanctions port.SanctionsReader,
+	hub port.RoomHub,
+	metrics port.Metrics,
+) *postMessageInteractor {
+	return &postMessageInteractor{
+		messages:  messages,
+		sanctions: sanctions,
+		hub:       hub,
+		metrics:   metrics,
+	}
+}
+
+func (it *postMessageInteractor) Post(ctx context.Context, input *PostMessageInput) (*PostMessageOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.PostMessageInteractor.Post")
+	defer span.End()
+	_, err := it.sanctions.Get(ctx, &port.GetSanctionInput{
+		RoomID: input.RoomID,
+		UserID: input.User.ID,
+		Type:   entity.SanctionTypeMute,
+	})
+	if err == nil {
+		return nil, usecase.ErrMuted
+	}
+	if !errors.Is(err, usecase.ErrNotFoundEntity) {
+		return nil, err
+	}
+
+	out, err := it.messages.Create(ctx, &port.CreateMessageInput{
+		RoomID:         input.RoomID,
+		Body:           input.Body,
+		PostedBy:       input.User,
+		IdempotencyKey: input.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// a retried post was already broadcast by the first one
+	if !out.Created {
+		return &PostMessageOutput{
+			Message: out.Message,
+		}, nil
+	}
+	it.metrics.IncMessagesReceived(ctx)
+
+	_, err = it.hub.Broadcast(ctx, &port.BroadcastRoomHubInput{
+		Event: &port.RoomEvent{
+			Type:    port.RoomEventTypeMessageCreated,
+			RoomID:  input.RoomID,
+			Message: out.Message,
+		},
+		ExcludeConnID: input.ConnID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostMessageOutput{
+		Message: out.Message,
+	}, nil
+}
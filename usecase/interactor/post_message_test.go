@@ -0,0 +1,82 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostMessageInteractor_Post(t *testing.T) {
+	user := &entity.User{ID: "01HNZ0000000000000000000AA", Name: "alice"}
+	message := &entity.PostMessage{ID: "01", RoomID: "room", Body: "hello", PostedBy: user}
+	input := &PostMessageInput{RoomID: "room", ConnID: "conn", Body: "hello", User: user, IdempotencyKey: "key"}
+	tests := []struct {
+		name          string
+		muted         bool
+		created       bool
+		wantBroadcast bool
+		wantErr       error
+	}{
+		{
+			name:          "broadcast created message",
+			created:       true,
+			wantBroadcast: true,
+		},
+		{
+			name:          "return original message without broadcast when idempotency key is reused",
+			created:       false,
+			wantBroadcast: false,
+		},
+		{
+			name:    "return ErrMuted without creating message when user is muted",
+			muted:   true,
+			wantErr: usecase.ErrMuted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			sanctions := mocks.NewSanctionsReader(t)
+			muteInput := &port.GetSanctionInput{RoomID: input.RoomID, UserID: user.ID, Type: entity.SanctionTypeMute}
+			if tt.muted {
+				sanctions.On("Get", mock.Anything, muteInput).
+					Return(&port.GetSanctionOutput{Sanction: &entity.Sanction{RoomID: input.RoomID, UserID: user.ID, Type: entity.SanctionTypeMute}}, nil)
+			} else {
+				sanctions.On("Get", mock.Anything, muteInput).Return(nil, usecase.ErrNotFoundEntity)
+			}
+			messages := mocks.NewMessagesWriter(t)
+			if !tt.muted {
+				messages.On("Create", mock.Anything, &port.CreateMessageInput{
+					RoomID:         input.RoomID,
+					Body:           input.Body,
+					PostedBy:       user,
+					IdempotencyKey: input.IdempotencyKey,
+				}).Return(&port.CreateMessageOutput{Message: message, Created: tt.created}, nil)
+			}
+			hub := mocks.NewRoomHub(t)
+			metrics := mocks.NewMetrics(t)
+			if tt.wantBroadcast {
+				metrics.On("IncMessagesReceived", mock.Anything).Return()
+				hub.On("Broadcast", mock.Anything, &port.BroadcastRoomHubInput{
+					Event:         &port.RoomEvent{Type: port.RoomEventTypeMessageCreated, RoomID: input.RoomID, Message: message},
+					ExcludeConnID: input.ConnID,
+				}).Return(&port.BroadcastRoomHubOutput{}, nil)
+			}
+
+			it := NewPostMessageInteractor(messages, sanctions, hub, metrics)
+			got, err := it.Post(ctx, input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &PostMessageOutput{Message: message}, got)
+		})
+	}
+}
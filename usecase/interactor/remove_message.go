@@ -0,0 +1,61 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/policy"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ RemoveMessageInteractor = (*removeMessageInteractor)(nil)
+
+type (
+	RemoveMessageInput struct {
+		RoomID    entity.ID
+		MessageID entity.ID
+		// ActorID is the user requesting it, whose role in the room must allow it.
+		ActorID entity.ID
+	}
+	RemoveMessageOutput struct{}
+	// RemoveMessageInteractor lets moderators remove a message of their room.
+	RemoveMessageInteractor interface {
+		Remove(ctx context.Context, input *RemoveMessageInput) (*RemoveMessageOutput, error)
+	}
+	removeMessageInteractor struct {
+		rooms    port.RoomsReader
+		messages port.MessagesWriter
+	}
+)
+
+func NewRemoveMessageInteractor(rooms port.RoomsReader, messages port.MessagesWriter) *removeMessageInteractor {
+	return &removeMessageInteractor{
+		rooms:    rooms,
+		messages: messages,
+	}
+}
+
+func (it *removeMessageInteractor) Remove(ctx context.Context, input *RemoveMessageInput) (*RemoveMessageOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.RemoveMessageInteractor.Remove")
+	defer span.End()
+	roomOut, err := it.rooms.Get(ctx, &port.GetRoomInput{
+		ID: input.RoomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.AuthorizeRoom(roomOut.Room, input.ActorID, policy.RoomActionRemoveMessage); err != nil {
+		return nil, err
+	}
+
+	_, err = it.messages.Delete(ctx, &port.DeleteMessagesInput{
+		RoomID:    input.RoomID,
+		MessageID: &input.MessageID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoveMessageOutput{}, nil
+}
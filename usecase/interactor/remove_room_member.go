@@ -0,0 +1,71 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/policy"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ RemoveRoomMemberInteractor = (*removeRoomMemberInteractor)(nil)
+
+type (
+	RemoveRoomMemberInput struct {
+		RoomID entity.ID
+		UserID entity.ID
+		// ActorID is the user requesting it, whose role in the room must allow it.
+		ActorID entity.ID
+	}
+	RemoveRoomMemberOutput struct{}
+	// RemoveRoomMemberInteractor removes a member from the room and closes their connections to it on every instance.
+	RemoveRoomMemberInteractor interface {
+		Remove(ctx context.Context, input *RemoveRoomMemberInput) (*RemoveRoomMemberOutput, error)
+	}
+	removeRoomMemberInteractor struct {
+		rooms port.RoomsManager
+		hub   port.RoomHub
+	}
+)
+
+func NewRemoveRoomMemberInteractor(rooms port.RoomsManager, hub port.RoomHub) *removeRoomMemberInteractor {
+	return &removeRoomMemberInteractor{
+		rooms: rooms,
+		hub:   hub,
+	}
+}
+
+func (it *removeRoomMemberInteractor) Remove(ctx context.Context, input *RemoveRoomMemberInput) (*RemoveRoomMemberOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.RemoveRoomMemberInteractor.Remove")
+	defer span.End()
+	roomOut, err := it.rooms.Get(ctx, &port.GetRoomInput{
+		ID: input.RoomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.AuthorizeRemoveMember(roomOut.Room, input.ActorID, input.UserID); err != nil {
+		return nil, err
+	}
+
+	_, err = it.rooms.RemoveMember(ctx, &port.RemoveRoomMemberInput{
+		RoomID: input.RoomID,
+		UserID: input.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = it.hub.Broadcast(ctx, &port.BroadcastRoomHubInput{
+		Event: &port.RoomEvent{
+			Type:   port.RoomEventTypeUserKicked,
+			RoomID: input.RoomID,
+			UserID: input.UserID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoveRoomMemberOutput{}, nil
+}
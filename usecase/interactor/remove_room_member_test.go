@@ -0,0 +1,87 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRemoveRoomMemberInteractor_Remove(t *testing.T) {
+	owner := &entity.User{ID: "01HNZ0000000000000000000U1", Name: "owner"}
+	moderator := &entity.User{ID: "01HNZ0000000000000000000U2", Name: "moderator"}
+	member := &entity.User{ID: "01HNZ0000000000000000000U3", Name: "member"}
+	room := &entity.Room{ID: "01HNZ0000000000000000000AA", Name: "room", Members: entity.Members{
+		{User: owner, Role: entity.RoleOwner},
+		{User: moderator, Role: entity.RoleModerator},
+		{User: member, Role: entity.RoleMember},
+	}}
+	tests := []struct {
+		name      string
+		actorID   entity.ID
+		userID    entity.ID
+		removeErr error
+		wantErr   error
+	}{
+		{
+			name:    "remove member and kick their connections",
+			actorID: moderator.ID,
+			userID:  member.ID,
+		},
+		{
+			name:    "member leaves on their own",
+			actorID: member.ID,
+			userID:  member.ID,
+		},
+		{
+			name:    "return ErrForbidden when member removes another member",
+			actorID: member.ID,
+			userID:  moderator.ID,
+			wantErr: usecase.ErrForbidden,
+		},
+		{
+			name:    "return ErrForbidden when owner leaves",
+			actorID: owner.ID,
+			userID:  owner.ID,
+			wantErr: usecase.ErrForbidden,
+		},
+		{
+			name:      "return ErrNotFoundEntity without kick when user is not a member",
+			actorID:   moderator.ID,
+			userID:    "01HNZ0000000000000000000U9",
+			removeErr: usecase.ErrNotFoundEntity,
+			wantErr:   usecase.ErrNotFoundEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rooms := mocks.NewRoomsManager(t)
+			hub := mocks.NewRoomHub(t)
+			rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: room.ID}).Return(&port.GetRoomOutput{Room: room}, nil)
+			if tt.wantErr == nil || tt.removeErr != nil {
+				rooms.On("RemoveMember", mock.Anything, &port.RemoveRoomMemberInput{RoomID: room.ID, UserID: tt.userID}).
+					Return(&port.RemoveRoomMemberOutput{}, tt.removeErr)
+			}
+			if tt.wantErr == nil {
+				hub.On("Broadcast", mock.Anything, &port.BroadcastRoomHubInput{
+					Event: &port.RoomEvent{Type: port.RoomEventTypeUserKicked, RoomID: room.ID, UserID: tt.userID},
+				}).Return(&port.BroadcastRoomHubOutput{}, nil)
+			}
+
+			it := NewRemoveRoomMemberInteractor(rooms, hub)
+			got, err := it.Remove(ctx, &RemoveRoomMemberInput{RoomID: room.ID, UserID: tt.userID, ActorID: tt.actorID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &RemoveRoomMemberOutput{}, got)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+const defaultReplayMessagesLimit = 100
+
+var _ ReplayMessagesInteractor = (*replayMessagesInteractor)(nil)
+
+type (
+	ReplayMessagesInput struct {
+		RoomID entity.ID
+		// Since is the last message the client has received.
+		Since entity.ID
+		Limit int
+	}
+	ReplayMessagesOutput struct {
+		// Messages are ordered oldest first.
+		Messages entity.PostMessages
+		// HasMore tells that newer messages remain after the last one of Messages.
+		HasMore bool
+	}
+	// ReplayMessagesInteractor returns the messages a reconnecting client has missed.
+	ReplayMessagesInteractor interface {
+		Replay(ctx context.Context, input *ReplayMessagesInput) (*ReplayMessagesOutput, error)
+	}
+	replayMessagesInteractor struct {
+		messages port.MessagesReader
+	}
+)
+
+func NewReplayMessagesInteractor(messages port.MessagesReader) *replayMessagesInteractor {
+	return &replayMessagesInteractor{
+		messages: messages,
+	}
+}
+
+func (it *replayMessagesInteractor) Replay(ctx context.Context, input *ReplayMessagesInput) (*ReplayMessagesOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.ReplayMessagesInteractor.Replay")
+	defer span.End()
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultReplayMessagesLimit
+	}
+	// one extra message tells whether a newer page exists
+	out, err := it.messages.Find(ctx, &port.FindMessagesInput{
+		RoomID: input.RoomID,
+		After:  &input.Since,
+		Limit:  limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := out.Messages
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	return &ReplayMessagesOutput{
+		Messages: messages,
+		HasMore:  hasMore,
+	}, nil
+}
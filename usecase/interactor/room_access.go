@@ -0,0 +1,34 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+)
+
+// getReadingMember returns the membership allowing the user to read the room.
+// ErrNotFoundEntity is returned when the user is not a member, and ErrForbidden while the user is banned.
+func getReadingMember(ctx context.Context, rooms port.RoomsReader, sanctions port.SanctionsReader, roomID entity.ID, userID entity.ID) (*entity.Member, error) {
+	memberOut, err := rooms.GetMember(ctx, &port.GetRoomMemberInput{
+		RoomID: roomID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = sanctions.Get(ctx, &port.GetSanctionInput{
+		RoomID: roomID,
+		UserID: userID,
+		Type:   entity.SanctionTypeBan,
+	})
+	if err == nil {
+		return nil, usecase.ErrForbidden
+	}
+	if !errors.Is(err, usecase.ErrNotFoundEntity) {
+		return nil, err
+	}
+	return memberOut.Member, nil
+}
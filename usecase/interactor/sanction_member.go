@@ -0,0 +1,110 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/policy"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ SanctionMemberInteractor = (*sanctionMemberInteractor)(nil)
+
+type (
+	SanctionMemberInput struct {
+		RoomID entity.ID
+		UserID entity.ID
+		Type   entity.SanctionType
+		// ExpiresAt is when the sanction is lifted by itself, it lasts until it is lifted when nil.
+		ExpiresAt *time.Time
+		// ActorID is the user requesting it, whose role in the room must allow it.
+		ActorID entity.ID
+	}
+	SanctionMemberOutput struct {
+		Sanction *entity.Sanction
+	}
+	// SanctionMemberInteractor bans or mutes a user in a room, replacing the expiry of the same sanction.
+	// A banned user is removed from the members and kicked as well, lifting the ban does not add them back.
+	SanctionMemberInteractor interface {
+		Sanction(ctx context.Context, input *SanctionMemberInput) (*SanctionMemberOutput, error)
+	}
+	sanctionMemberInteractor struct {
+		rooms     port.RoomsManager
+		users     port.UsersReader
+		sanctions port.SanctionsWriter
+		hub       port.RoomHub
+	}
+)
+
+func NewSanctionMemberInteractor(
+	rooms port.RoomsManager,
+	users port.UsersReader,
+	sanctions port.SanctionsWriter,
+	hub port.RoomHub,
+) *sanctionMemberInteractor {
+	return &sanctionMemberInteractor{
+		rooms:     rooms,
+		users:     users,
+		sanctions: sanctions,
+		hub:       hub,
+	}
+}
+
+func (it *sanctionMemberInteractor) Sanction(ctx context.Context, input *SanctionMemberInput) (*SanctionMemberOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.SanctionMemberInteractor.Sanction")
+	defer span.End()
+	roomOut, err := it.rooms.Get(ctx, &port.GetRoomInput{
+		ID: input.RoomID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.AuthorizeModerate(roomOut.Room, input.ActorID, input.UserID); err != nil {
+		return nil, err
+	}
+	if _, err := it.users.Get(ctx, &port.GetUserInput{
+		ID: input.UserID,
+	}); err != nil {
+		return nil, err
+	}
+
+	out, err := it.sanctions.Put(ctx, &port.PutSanctionInput{
+		Sanction: &entity.Sanction{
+			RoomID:    input.RoomID,
+			UserID:    input.UserID,
+			Type:      input.Type,
+			ExpiresAt: input.ExpiresAt,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if input.Type == entity.SanctionTypeBan {
+		// users who are not members can be banned in advance
+		_, err = it.rooms.RemoveMember(ctx, &port.RemoveRoomMemberInput{
+			RoomID: input.RoomID,
+			UserID: input.UserID,
+		})
+		if err != nil && !errors.Is(err, usecase.ErrNotFoundEntity) {
+			return nil, err
+		}
+		_, err = it.hub.Broadcast(ctx, &port.BroadcastRoomHubInput{
+			Event: &port.RoomEvent{
+				Type:   port.RoomEventTypeUserKicked,
+				RoomID: input.RoomID,
+				UserID: input.UserID,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &SanctionMemberOutput{
+		Sanction: out.Sanction,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSanctionMemberInteractor_Sanction(t *testing.T) {
+	moderator := &entity.User{ID: "01HNZ0000000000000000000U1", Name: "moderator"}
+	member := &entity.User{ID: "01HNZ0000000000000000000U2", Name: "member"}
+	room := &entity.Room{
+		ID:   "01HNZ0000000000000000000AA",
+		Name: "room",
+		Members: entity.Members{
+			{User: moderator, Role: entity.RoleModerator},
+			{User: member, Role: entity.RoleMember},
+		},
+	}
+	expiresAt := time.Date(2024, 2, 8, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		input    *SanctionMemberInput
+		wantKick bool
+		wantErr  error
+	}{
+		{
+			name:     "ban and kick member",
+			input:    &SanctionMemberInput{RoomID: room.ID, UserID: member.ID, Type: entity.SanctionTypeBan, ExpiresAt: &expiresAt, ActorID: moderator.ID},
+			wantKick: true,
+		},
+		{
+			name:     "ban user who is not a member",
+			input:    &SanctionMemberInput{RoomID: room.ID, UserID: "01HNZ0000000000000000000U9", Type: entity.SanctionTypeBan, ActorID: moderator.ID},
+			wantKick: true,
+		},
+		{
+			name:  "mute member without kicking",
+			input: &SanctionMemberInput{RoomID: room.ID, UserID: member.ID, Type: entity.SanctionTypeMute, ActorID: moderator.ID},
+		},
+		{
+			name:    "return ErrForbidden when member sanctions moderator",
+			input:   &SanctionMemberInput{RoomID: room.ID, UserID: moderator.ID, Type: entity.SanctionTypeBan, ActorID: member.ID},
+			wantErr: usecase.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rooms := mocks.NewRoomsManager(t)
+			users := mocks.NewUsersReader(t)
+			sanctions := mocks.NewSanctionsWriter(t)
+			hub := mocks.NewRoomHub(t)
+			sanction := &entity.Sanction{RoomID: room.ID, UserID: tt.input.UserID, Type: tt.input.Type, ExpiresAt: tt.input.ExpiresAt}
+			rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: room.ID}).Return(&port.GetRoomOutput{Room: room}, nil)
+			if tt.wantErr == nil {
+				users.On("Get", mock.Anything, &port.GetUserInput{ID: tt.input.UserID}).Return(&port.GetUserOutput{User: member}, nil)
+				sanctions.On("Put", mock.Anything, &port.PutSanctionInput{Sanction: sanction}).
+					Return(&port.PutSanctionOutput{Sanction: sanction}, nil)
+			}
+			if tt.wantKick {
+				var removeErr error
+				if room.Members.Find(tt.input.UserID) == nil {
+					removeErr = usecase.ErrNotFoundEntity
+				}
+				rooms.On("RemoveMember", mock.Anything, &port.RemoveRoomMemberInput{RoomID: room.ID, UserID: tt.input.UserID}).
+					Return(&port.RemoveRoomMemberOutput{}, removeErr)
+				hub.On("Broadcast", mock.Anything, &port.BroadcastRoomHubInput{
+					Event: &port.RoomEvent{Type: port.RoomEventTypeUserKicked, RoomID: room.ID, UserID: tt.input.UserID},
+				}).Return(&port.BroadcastRoomHubOutput{}, nil)
+			}
+
+			it := NewSanctionMemberInteractor(rooms, users, sanctions, hub)
+			got, err := it.Sanction(ctx, tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &SanctionMemberOutput{Sanction: sanction}, got)
+		})
+	}
+}
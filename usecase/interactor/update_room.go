@@ -0,0 +1,82 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase/policy"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/mkaiho/go-ws-sample/util"
+)
+
+var _ UpdateRoomInteractor = (*updateRoomInteractor)(nil)
+
+type (
+	UpdateRoomInput struct {
+		ID entity.ID
+		// Name is left unchanged when nil.
+		Name *string
+		// Description is left unchanged when nil, and removed when it points to nil.
+		Description **string
+		// Version is the version the room must have, any version is accepted when nil.
+		Version *int
+		// ActorID is the user requesting it, whose role in the room must allow it.
+		ActorID entity.ID
+	}
+	UpdateRoomOutput struct {
+		Room *entity.Room
+	}
+	UpdateRoomInteractor interface {
+		Update(ctx context.Context, input *UpdateRoomInput) (*UpdateRoomOutput, error)
+	}
+	updateRoomInteractor struct {
+		rooms port.RoomsManager
+		hub   port.RoomHub
+	}
+)
+
+func NewUpdateRoomInteractor(rooms port.RoomsManager, hub port.RoomHub) *updateRoomInteractor {
+	return &updateRoomInteractor{
+		rooms: rooms,
+		hub:   hub,
+	}
+}
+
+func (it *updateRoomInteractor) Update(ctx context.Context, input *UpdateRoomInput) (*UpdateRoomOutput, error) {
+	ctx, span := util.StartSpan(ctx, "interactor.UpdateRoomInteractor.Update")
+	defer span.End()
+	roomOut, err := it.rooms.Get(ctx, &port.GetRoomInput{
+		ID: input.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.AuthorizeRoom(roomOut.Room, input.ActorID, policy.RoomActionUpdate); err != nil {
+		return nil, err
+	}
+
+	out, err := it.rooms.Update(ctx, &port.UpdateRoomInput{
+		ID:          input.ID,
+		Name:        input.Name,
+		Description: input.Description,
+		Version:     input.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = it.hub.Broadcast(ctx, &port.BroadcastRoomHubInput{
+		Event: &port.RoomEvent{
+			Type:   port.RoomEventTypeRoomUpdated,
+			RoomID: out.Room.ID,
+			Room:   out.Room,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateRoomOutput{
+		Room: out.Room,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	mocks "github.com/mkaiho/go-ws-sample/mocks/usecase/port"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/mkaiho/go-ws-sample/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateRoomInteractor_Update(t *testing.T) {
+	owner := &entity.User{ID: "01HNZ0000000000000000000U1", Name: "owner"}
+	member := &entity.User{ID: "01HNZ0000000000000000000U2", Name: "member"}
+	members := entity.Members{
+		{User: owner, Role: entity.RoleOwner},
+		{User: member, Role: entity.RoleMember},
+	}
+	name := "renamed"
+	stored := &entity.Room{ID: "01HNZ0000000000000000000AA", Name: "room", Members: members}
+	room := &entity.Room{ID: stored.ID, Name: name, Members: members}
+	tests := []struct {
+		name    string
+		actorID entity.ID
+		getErr  error
+		want    *UpdateRoomOutput
+		wantErr error
+	}{
+		{
+			name:    "broadcast updated room",
+			actorID: owner.ID,
+			want:    &UpdateRoomOutput{Room: room},
+		},
+		{
+			name:    "return ErrNotFoundEntity without broadcast when room does not exist",
+			actorID: owner.ID,
+			getErr:  usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+		{
+			name:    "return ErrForbidden without update when user is not the owner",
+			actorID: member.ID,
+			wantErr: usecase.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rooms := mocks.NewRoomsManager(t)
+			hub := mocks.NewRoomHub(t)
+			if tt.getErr != nil {
+				rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: stored.ID}).Return(nil, tt.getErr)
+			} else {
+				rooms.On("Get", mock.Anything, &port.GetRoomInput{ID: stored.ID}).Return(&port.GetRoomOutput{Room: stored}, nil)
+			}
+			if tt.wantErr == nil {
+				rooms.On("Update", mock.Anything, &port.UpdateRoomInput{ID: stored.ID, Name: &name}).
+					Return(&port.UpdateRoomOutput{Room: room}, nil)
+				hub.On("Broadcast", mock.Anything, &port.BroadcastRoomHubInput{
+					Event: &port.RoomEvent{Type: port.RoomEventTypeRoomUpdated, RoomID: room.ID, Room: room},
+				}).Return(&port.BroadcastRoomHubOutput{}, nil)
+			}
+
+			it := NewUpdateRoomInteractor(rooms, hub)
+			got, err := it.Update(ctx, &UpdateRoomInput{ID: stored.ID, Name: &name, ActorID: tt.actorID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
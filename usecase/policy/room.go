@@ -0,0 +1,101 @@
+package policy
+
+import (
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+)
+
+// RoomAction is something a member can do in a room beyond reading and posting messages.
+type RoomAction string
+
+const (
+	// RoomActionUpdate renames the room or changes its description.
+	RoomActionUpdate RoomAction = "update"
+	// RoomActionDelete deletes the room with its messages.
+	RoomActionDelete RoomAction = "delete"
+	// RoomActionRemoveMessage removes a message posted by anyone.
+	RoomActionRemoveMessage RoomAction = "remove_message"
+	// RoomActionManageMembers adds and removes members.
+	RoomActionManageMembers RoomAction = "manage_members"
+	// RoomActionModerate kicks, bans and mutes users.
+	RoomActionModerate RoomAction = "moderate"
+)
+
+// roomActionRoles is the least role allowed to take each action.
+var roomActionRoles = map[RoomAction]entity.Role{
+	RoomActionUpdate:        entity.RoleOwner,
+	RoomActionDelete:        entity.RoleOwner,
+	RoomActionRemoveMessage: entity.RoleModerator,
+	RoomActionManageMembers: entity.RoleModerator,
+	RoomActionModerate:      entity.RoleModerator,
+}
+
+// AuthorizeRoom returns usecase.ErrForbidden unless the user is a member of the room
+// whose role allows the action.
+func AuthorizeRoom(room *entity.Room, userID entity.ID, action RoomAction) error {
+	member := room.Members.Find(userID)
+	if member == nil {
+		return usecase.ErrForbidden
+	}
+	least, ok := roomActionRoles[action]
+	if !ok || member.Role.Rank() < least.Rank() {
+		return usecase.ErrForbidden
+	}
+	return nil
+}
+
+// AuthorizeAddMember returns usecase.ErrForbidden unless the user may add a member with the role.
+// Moderators add plain members, owners also add moderators, and nobody adds another owner.
+func AuthorizeAddMember(room *entity.Room, userID entity.ID, role entity.Role) error {
+	if err := AuthorizeRoom(room, userID, RoomActionManageMembers); err != nil {
+		return err
+	}
+	member := room.Members.Find(userID)
+	if role == entity.RoleOwner || role.Rank() >= member.Role.Rank() {
+		return usecase.ErrForbidden
+	}
+	return nil
+}
+
+// AuthorizeRemoveMember returns usecase.ErrForbidden unless the user may remove the target from the room.
+// Members may leave on their own except the owner, and otherwise only members of a lower role can be removed.
+// A target that is not a member is left to the caller to report.
+func AuthorizeRemoveMember(room *entity.Room, userID entity.ID, targetID entity.ID) error {
+	member := room.Members.Find(userID)
+	if member == nil {
+		return usecase.ErrForbidden
+	}
+	target := room.Members.Find(targetID)
+	if target == nil {
+		return nil
+	}
+	if target.Role == entity.RoleOwner {
+		return usecase.ErrForbidden
+	}
+	if userID == targetID {
+		return nil
+	}
+	if err := AuthorizeRoom(room, userID, RoomActionManageMembers); err != nil {
+		return err
+	}
+	if target.Role.Rank() >= member.Role.Rank() {
+		return usecase.ErrForbidden
+	}
+	return nil
+}
+
+// AuthorizeModerate returns usecase.ErrForbidden unless the user may kick, ban or mute the target in the room.
+// Only users of a lower role can be moderated, which includes users who are not members.
+func AuthorizeModerate(room *entity.Room, userID entity.ID, targetID entity.ID) error {
+	if err := AuthorizeRoom(room, userID, RoomActionModerate); err != nil {
+		return err
+	}
+	if userID == targetID {
+		return usecase.ErrForbidden
+	}
+	member := room.Members.Find(userID)
+	if target := room.Members.Find(targetID); target != nil && target.Role.Rank() >= member.Role.Rank() {
+		return usecase.ErrForbidden
+	}
+	return nil
+}
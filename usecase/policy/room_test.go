@@ -0,0 +1,131 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+	"github.com/mkaiho/go-ws-sample/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRoom() *entity.Room {
+	return &entity.Room{
+		ID: "01HNZ0000000000000000000AA",
+		Members: entity.Members{
+			{User: &entity.User{ID: "owner"}, Role: entity.RoleOwner},
+			{User: &entity.User{ID: "moderator"}, Role: entity.RoleModerator},
+			{User: &entity.User{ID: "moderator2"}, Role: entity.RoleModerator},
+			{User: &entity.User{ID: "member"}, Role: entity.RoleMember},
+			{User: &entity.User{ID: "member2"}, Role: entity.RoleMember},
+		},
+	}
+}
+
+func TestAuthorizeRoom(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  entity.ID
+		action  RoomAction
+		wantErr error
+	}{
+		{name: "owner can delete", userID: "owner", action: RoomActionDelete},
+		{name: "owner can update", userID: "owner", action: RoomActionUpdate},
+		{name: "owner can remove messages", userID: "owner", action: RoomActionRemoveMessage},
+		{name: "moderator can remove messages", userID: "moderator", action: RoomActionRemoveMessage},
+		{name: "moderator cannot delete", userID: "moderator", action: RoomActionDelete, wantErr: usecase.ErrForbidden},
+		{name: "moderator cannot update", userID: "moderator", action: RoomActionUpdate, wantErr: usecase.ErrForbidden},
+		{name: "member cannot remove messages", userID: "member", action: RoomActionRemoveMessage, wantErr: usecase.ErrForbidden},
+		{name: "non member cannot remove messages", userID: "stranger", action: RoomActionRemoveMessage, wantErr: usecase.ErrForbidden},
+		{name: "unknown action is refused", userID: "owner", action: RoomAction("unknown"), wantErr: usecase.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AuthorizeRoom(testRoom(), tt.userID, tt.action)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthorizeAddMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  entity.ID
+		role    entity.Role
+		wantErr error
+	}{
+		{name: "owner can add moderator", userID: "owner", role: entity.RoleModerator},
+		{name: "moderator can add member", userID: "moderator", role: entity.RoleMember},
+		{name: "moderator cannot add moderator", userID: "moderator", role: entity.RoleModerator, wantErr: usecase.ErrForbidden},
+		{name: "owner cannot add owner", userID: "owner", role: entity.RoleOwner, wantErr: usecase.ErrForbidden},
+		{name: "member cannot add member", userID: "member", role: entity.RoleMember, wantErr: usecase.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AuthorizeAddMember(testRoom(), tt.userID, tt.role)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthorizeRemoveMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   entity.ID
+		targetID entity.ID
+		wantErr  error
+	}{
+		{name: "member can leave", userID: "member", targetID: "member"},
+		{name: "moderator can remove member", userID: "moderator", targetID: "member"},
+		{name: "owner can remove moderator", userID: "owner", targetID: "moderator"},
+		{name: "unknown target is left to the caller", userID: "moderator", targetID: "stranger"},
+		{name: "owner cannot leave", userID: "owner", targetID: "owner", wantErr: usecase.ErrForbidden},
+		{name: "moderator cannot remove moderator", userID: "moderator", targetID: "moderator2", wantErr: usecase.ErrForbidden},
+		{name: "member cannot remove member", userID: "member", targetID: "member2", wantErr: usecase.ErrForbidden},
+		{name: "non member cannot remove member", userID: "stranger", targetID: "member", wantErr: usecase.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AuthorizeRemoveMember(testRoom(), tt.userID, tt.targetID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthorizeModerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   entity.ID
+		targetID entity.ID
+		wantErr  error
+	}{
+		{name: "moderator can moderate member", userID: "moderator", targetID: "member"},
+		{name: "moderator can moderate non member", userID: "moderator", targetID: "stranger"},
+		{name: "owner can moderate moderator", userID: "owner", targetID: "moderator"},
+		{name: "moderator cannot moderate moderator", userID: "moderator", targetID: "moderator2", wantErr: usecase.ErrForbidden},
+		{name: "moderator cannot moderate owner", userID: "moderator", targetID: "owner", wantErr: usecase.ErrForbidden},
+		{name: "owner cannot moderate self", userID: "owner", targetID: "owner", wantErr: usecase.ErrForbidden},
+		{name: "member cannot moderate", userID: "member", targetID: "member2", wantErr: usecase.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AuthorizeModerate(testRoom(), tt.userID, tt.targetID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+)
+
+type (
+	// MessageBusHandler receives the payloads published to a subscribed topic.
+	// It must not block, implementations may call it on the publisher's goroutine.
+	MessageBusHandler func(ctx context.Context, payload []byte)
+	// MessageBusSubscription stops the delivery to its handler once unsubscribed.
+	MessageBusSubscription interface {
+		Unsubscribe(ctx context.Context) error
+	}
+)
+
+type (
+	PublishMessageBusInput struct {
+		Topic   entity.ID
+		Payload []byte
+	}
+	PublishMessageBusOutput  struct{}
+	SubscribeMessageBusInput struct {
+		Topic   entity.ID
+		Handler MessageBusHandler
+	}
+	SubscribeMessageBusOutput struct {
+		Subscription MessageBusSubscription
+	}
+	// MessageBus delivers payloads to every subscriber of a topic, on any server instance.
+	// Subscribe returns once the subscription is active, so payloads published afterwards are not missed.
+	MessageBus interface {
+		Publish(ctx context.Context, input *PublishMessageBusInput) (*PublishMessageBusOutput, error)
+		Subscribe(ctx context.Context, input *SubscribeMessageBusInput) (*SubscribeMessageBusOutput, error)
+	}
+)
@@ -0,0 +1,58 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+)
+
+type (
+	FindMessagesInput struct {
+		RoomID entity.ID
+		// Before limits the result to messages older than the message with this ID.
+		Before *entity.ID
+		// After limits the result to messages newer than the message with this ID
+		// and orders it oldest first.
+		After *entity.ID
+		Limit int
+	}
+	FindMessagesOutput struct {
+		// Messages are ordered newest first unless After is specified.
+		Messages entity.PostMessages
+	}
+	MessagesReader interface {
+		Find(ctx context.Context, input *FindMessagesInput) (*FindMessagesOutput, error)
+	}
+)
+
+type (
+	CreateMessageInput struct {
+		RoomID   entity.ID
+		Body     string
+		PostedBy *entity.User
+		// IdempotencyKey identifies a post of the user within the room.
+		// When a message was already created with the key, it is returned instead of creating another one.
+		IdempotencyKey string
+	}
+	CreateMessageOutput struct {
+		Message *entity.PostMessage
+		// Created is false when the message was found by the idempotency key.
+		Created bool
+	}
+	// DeleteMessagesInput deletes every message of the room, or only the message of MessageID.
+	DeleteMessagesInput struct {
+		RoomID entity.ID
+		// MessageID is reported as ErrNotFoundEntity when the room has no such message.
+		MessageID *entity.ID
+	}
+	DeleteMessagesOutput struct{}
+	MessagesWriter       interface {
+		Create(ctx context.Context, input *CreateMessageInput) (*CreateMessageOutput, error)
+		Delete(ctx context.Context, input *DeleteMessagesInput) (*DeleteMessagesOutput, error)
+	}
+)
+
+type MessagesManager interface {
+	MessagesReader
+	MessagesWriter
+}
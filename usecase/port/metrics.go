@@ -0,0 +1,22 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+)
+
+// Metrics records the activity of the service for monitoring.
+type Metrics interface {
+	// ObserveHTTPRequest records a served request, route is the template such as /rooms/:room_id.
+	ObserveHTTPRequest(ctx context.Context, method string, route string, status int, duration time.Duration)
+	// AddConnections changes the number of open WebSocket connections of the room by delta.
+	AddConnections(ctx context.Context, roomID entity.ID, delta int)
+	// IncMessagesReceived counts a message posted by a client.
+	IncMessagesReceived(ctx context.Context)
+	// IncMessagesSent counts a message delivered to a connection.
+	IncMessagesSent(ctx context.Context)
+	// IncSlowConsumersDropped counts a connection closed because it could not keep up with its room.
+	IncSlowConsumersDropped(ctx context.Context)
+}
@@ -0,0 +1,9 @@
+package port
+
+import "context"
+
+type PasswordHasher interface {
+	Hash(ctx context.Context, password string) ([]byte, error)
+	// Compare returns usecase.ErrInvalidCredential when the password does not match the hash.
+	Compare(ctx context.Context, hash []byte, password string) error
+}
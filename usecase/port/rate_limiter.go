@@ -0,0 +1,30 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimit allows Rate events per second on average, and bursts of up to Burst events.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type (
+	AllowRateInput struct {
+		// Key identifies the bucket the event is taken from, such as a route and a user.
+		Key   string
+		Limit RateLimit
+	}
+	AllowRateOutput struct {
+		Allowed bool
+		// RetryAfter is the time until an event is allowed again when Allowed is false.
+		RetryAfter time.Duration
+	}
+)
+
+type RateLimiter interface {
+	// Allow takes an event from the token bucket of the key.
+	Allow(ctx context.Context, input *AllowRateInput) (*AllowRateOutput, error)
+}
@@ -0,0 +1,91 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+)
+
+type CloseReason int
+
+const (
+	CloseReasonNormal CloseReason = iota
+	CloseReasonSlowConsumer
+	CloseReasonGoingAway
+	CloseReasonRoomDeleted
+	CloseReasonKicked
+)
+
+func (r CloseReason) String() string {
+	switch r {
+	case CloseReasonNormal:
+		return "normal"
+	case CloseReasonSlowConsumer:
+		return "slow consumer"
+	case CloseReasonGoingAway:
+		return "going away"
+	case CloseReasonRoomDeleted:
+		return "room deleted"
+	case CloseReasonKicked:
+		return "kicked"
+	default:
+		return ""
+	}
+}
+
+type RoomEventType int
+
+const (
+	RoomEventTypeMessageCreated RoomEventType = iota
+	RoomEventTypeRoomUpdated
+	// RoomEventTypeRoomDeleted is the last event of a room, the hub closes the connections after it.
+	RoomEventTypeRoomDeleted
+	// RoomEventTypeUserKicked closes the connections of the user in the room, it is not sent to any connection.
+	RoomEventTypeUserKicked
+)
+
+type (
+	// RoomEvent carries Message for RoomEventTypeMessageCreated and Room for RoomEventTypeRoomUpdated.
+	// RoomEventTypeRoomDeleted carries only RoomID, and RoomEventTypeUserKicked carries UserID as well.
+	RoomEvent struct {
+		Type    RoomEventType
+		RoomID  entity.ID
+		Message *entity.PostMessage
+		Room    *entity.Room
+		UserID  entity.ID
+	}
+	// RoomHubConn is a live client connection registered to a RoomHub.
+	// Send is only called from the writer goroutine the hub owns for the connection.
+	RoomHubConn interface {
+		Send(ctx context.Context, event *RoomEvent) error
+		Close(ctx context.Context, reason CloseReason) error
+	}
+)
+
+type (
+	JoinRoomHubInput struct {
+		RoomID entity.ID
+		// UserID is the user of the connection, RoomEventTypeUserKicked closes it by this.
+		UserID entity.ID
+		Conn   RoomHubConn
+	}
+	JoinRoomHubOutput struct {
+		ConnID entity.ID
+	}
+	LeaveRoomHubInput struct {
+		RoomID entity.ID
+		ConnID entity.ID
+	}
+	LeaveRoomHubOutput    struct{}
+	BroadcastRoomHubInput struct {
+		Event *RoomEvent
+		// ExcludeConnID is the connection that must not receive the event, typically the sender.
+		ExcludeConnID entity.ID
+	}
+	BroadcastRoomHubOutput struct{}
+	RoomHub                interface {
+		Join(ctx context.Context, input *JoinRoomHubInput) (*JoinRoomHubOutput, error)
+		Leave(ctx context.Context, input *LeaveRoomHubInput) (*LeaveRoomHubOutput, error)
+		Broadcast(ctx context.Context, input *BroadcastRoomHubInput) (*BroadcastRoomHubOutput, error)
+	}
+)
@@ -6,8 +6,32 @@ import (
 	"github.com/mkaiho/go-ws-sample/entity"
 )
 
+type RoomsSortKey string
+
+const (
+	// RoomsSortKeyCreated orders rooms oldest first.
+	RoomsSortKeyCreated RoomsSortKey = "created"
+	// RoomsSortKeyName orders rooms by name, and rooms with the same name oldest first.
+	RoomsSortKeyName RoomsSortKey = "name"
+)
+
 type (
-	FindRoomsInput  struct{}
+	// RoomsPosition is the position of a room in the order of a RoomsSortKey.
+	// Name is only used by RoomsSortKeyName.
+	RoomsPosition struct {
+		ID   entity.ID
+		Name string
+	}
+	FindRoomsInput struct {
+		// Query limits the result to rooms whose name contains it, ignoring case.
+		Query string
+		// Sort defaults to RoomsSortKeyCreated.
+		Sort RoomsSortKey
+		// After limits the result to rooms ordered after this position.
+		After *RoomsPosition
+		// Limit is the maximum number of rooms, all rooms are returned when it is zero.
+		Limit int
+	}
 	FindRoomsOutput struct {
 		Rooms entity.Rooms
 	}
@@ -17,9 +41,24 @@ type (
 	GetRoomOutput struct {
 		Room *entity.Room
 	}
+	FindRoomMembersInput struct {
+		RoomID entity.ID
+	}
+	FindRoomMembersOutput struct {
+		Members entity.Members
+	}
+	GetRoomMemberInput struct {
+		RoomID entity.ID
+		UserID entity.ID
+	}
+	GetRoomMemberOutput struct {
+		Member *entity.Member
+	}
 	RoomsReader interface {
 		Find(ctx context.Context, input *FindRoomsInput) (*FindRoomsOutput, error)
 		Get(ctx context.Context, input *GetRoomInput) (*GetRoomOutput, error)
+		FindMembers(ctx context.Context, input *FindRoomMembersInput) (*FindRoomMembersOutput, error)
+		GetMember(ctx context.Context, input *GetRoomMemberInput) (*GetRoomMemberOutput, error)
 	}
 )
 
@@ -27,17 +66,51 @@ type (
 	CreateRoomInput struct {
 		Name        string
 		Description *string
+		// Owner is added to the room as its first member with entity.RoleOwner.
+		Owner *entity.User
 	}
 	CreateRoomOutput struct {
 		Room *entity.Room
 	}
+	UpdateRoomInput struct {
+		ID entity.ID
+		// Name is left unchanged when nil.
+		Name *string
+		// Description is left unchanged when nil, and removed when it points to nil.
+		Description **string
+		// Version is the version the room must have, ErrVersionConflict is returned otherwise.
+		// Any version is accepted when nil.
+		Version *int
+	}
+	UpdateRoomOutput struct {
+		Room *entity.Room
+	}
 	DeleteRoomInput struct {
 		ID entity.ID
+		// Version is the version the room must have, any version is accepted when nil.
+		Version *int
+	}
+	DeleteRoomOutput   struct{}
+	AddRoomMemberInput struct {
+		RoomID entity.ID
+		User   *entity.User
+		Role   entity.Role
+	}
+	AddRoomMemberOutput struct {
+		Member *entity.Member
+	}
+	RemoveRoomMemberInput struct {
+		RoomID entity.ID
+		UserID entity.ID
 	}
-	DeleteRoomOutput struct{}
-	RoomsWriter      interface {
+	RemoveRoomMemberOutput struct{}
+	RoomsWriter            interface {
 		Create(ctx context.Context, input *CreateRoomInput) (*CreateRoomOutput, error)
+		Update(ctx context.Context, input *UpdateRoomInput) (*UpdateRoomOutput, error)
+		// Delete removes the room along with its members, messages and sanctions.
 		Delete(ctx context.Context, input *DeleteRoomInput) (*DeleteRoomOutput, error)
+		AddMember(ctx context.Context, input *AddRoomMemberInput) (*AddRoomMemberOutput, error)
+		RemoveMember(ctx context.Context, input *RemoveRoomMemberInput) (*RemoveRoomMemberOutput, error)
 	}
 )
 
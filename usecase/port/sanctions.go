@@ -0,0 +1,48 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+)
+
+type (
+	// GetSanctionInput returns ErrNotFoundEntity when the user has no active sanction of the type in the room.
+	GetSanctionInput struct {
+		RoomID entity.ID
+		UserID entity.ID
+		Type   entity.SanctionType
+	}
+	GetSanctionOutput struct {
+		Sanction *entity.Sanction
+	}
+	SanctionsReader interface {
+		Get(ctx context.Context, input *GetSanctionInput) (*GetSanctionOutput, error)
+	}
+)
+
+type (
+	// PutSanctionInput replaces the sanction of the same room, user and type if there is one.
+	PutSanctionInput struct {
+		Sanction *entity.Sanction
+	}
+	PutSanctionOutput struct {
+		Sanction *entity.Sanction
+	}
+	// DeleteSanctionInput returns ErrNotFoundEntity when there is no such sanction.
+	DeleteSanctionInput struct {
+		RoomID entity.ID
+		UserID entity.ID
+		Type   entity.SanctionType
+	}
+	DeleteSanctionOutput struct{}
+	SanctionsWriter      interface {
+		Put(ctx context.Context, input *PutSanctionInput) (*PutSanctionOutput, error)
+		Delete(ctx context.Context, input *DeleteSanctionInput) (*DeleteSanctionOutput, error)
+	}
+)
+
+type SanctionsManager interface {
+	SanctionsReader
+	SanctionsWriter
+}
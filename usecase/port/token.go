@@ -0,0 +1,30 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+)
+
+type (
+	IssueTokenInput struct {
+		User *entity.User
+	}
+	IssueTokenOutput struct {
+		Token     string
+		ExpiresAt time.Time
+	}
+	VerifyTokenInput struct {
+		Token string
+	}
+	VerifyTokenOutput struct {
+		UserID entity.ID
+	}
+	// TokenManager issues and verifies access tokens.
+	// Verify returns usecase.ErrInvalidCredential for expired, malformed or forged tokens.
+	TokenManager interface {
+		Issue(ctx context.Context, input *IssueTokenInput) (*IssueTokenOutput, error)
+		Verify(ctx context.Context, input *VerifyTokenInput) (*VerifyTokenOutput, error)
+	}
+)
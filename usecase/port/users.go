@@ -0,0 +1,45 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-ws-sample/entity"
+)
+
+type (
+	GetUserInput struct {
+		ID entity.ID
+	}
+	GetUserOutput struct {
+		User *entity.User
+	}
+	GetUserCredentialInput struct {
+		Name string
+	}
+	GetUserCredentialOutput struct {
+		User         *entity.User
+		PasswordHash []byte
+	}
+	UsersReader interface {
+		Get(ctx context.Context, input *GetUserInput) (*GetUserOutput, error)
+		GetCredential(ctx context.Context, input *GetUserCredentialInput) (*GetUserCredentialOutput, error)
+	}
+)
+
+type (
+	CreateUserInput struct {
+		Name         string
+		PasswordHash []byte
+	}
+	CreateUserOutput struct {
+		User *entity.User
+	}
+	UsersWriter interface {
+		Create(ctx context.Context, input *CreateUserInput) (*CreateUserOutput, error)
+	}
+)
+
+type UsersManager interface {
+	UsersReader
+	UsersWriter
+}
@@ -70,9 +70,9 @@ func ParseLoggerLevelStr(v string) LoggerLevel {
 	case LoggerLevelDebug.String():
 		return LoggerLevelDebug
 	case LoggerLevelWarn.String():
-		return LoggerLevelDebug
+		return LoggerLevelWarn
 	case LoggerLevelError.String():
-		return LoggerLevelDebug
+		return LoggerLevelError
 	}
 }
 
@@ -0,0 +1,16 @@
+package util
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/mkaiho/go-ws-sample"
+
+// StartSpan starts a span as a child of the span in ctx with the global tracer provider.
+// The caller must end the returned span.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}